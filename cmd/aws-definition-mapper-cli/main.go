@@ -0,0 +1,162 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ernestio/aws-definition-mapper/definition"
+	"github.com/ernestio/aws-definition-mapper/mapper"
+	"github.com/ernestio/aws-definition-mapper/output"
+	"github.com/ghodss/yaml"
+)
+
+var (
+	definitionPath = flag.String("definition", "-", "yaml or json service definition, - reads from stdin")
+	datacenterPath = flag.String("datacenter", "", "yaml or json datacenter, - reads from stdin")
+	previousPath   = flag.String("previous", "", "json mapping of a previous build to diff against")
+	serviceID      = flag.String("id", "", "id of the service build")
+	clientName     = flag.String("client", "", "name of the client owning the service")
+	variablesPath  = flag.String("variables", "", "yaml or json values overriding the definitions variable defaults")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [options]\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Maps a service definition to a fsm message without connecting to nats.")
+		fmt.Fprintln(os.Stderr, "")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *datacenterPath == "" {
+		fmt.Fprintln(os.Stderr, "please specify a datacenter")
+		os.Exit(2)
+	}
+
+	if *definitionPath == "-" && *datacenterPath == "-" {
+		fmt.Fprintln(os.Stderr, "only one of the definition or datacenter can be read from stdin")
+		os.Exit(2)
+	}
+
+	m, err := mapDefinition()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, string(err.JSON()))
+		os.Exit(1)
+	}
+
+	var data []byte
+
+	// never print credentials or secrets
+	rm, merr := m.Redacted()
+	if merr == nil {
+		data, merr = json.MarshalIndent(rm, "", "  ")
+	}
+	if merr != nil {
+		fmt.Fprintln(os.Stderr, string(output.NewError(output.ERRMARSHALFAILED, "Failed marshal output message.", nil).JSON()))
+		os.Exit(1)
+	}
+
+	fmt.Println(string(data))
+}
+
+// mapDefinition runs the same steps as a creation request on definition.map.creation.aws
+func mapDefinition() (*output.FSMMessage, *output.Error) {
+	var om output.FSMMessage
+
+	p, err := loadPayload()
+	if _, ok := err.(definition.ValidationErrors); ok {
+		return nil, mapper.ValidationError(err)
+	}
+	if err != nil {
+		return nil, output.NewError(output.ERRINVALIDPAYLOAD, err.Error(), nil)
+	}
+
+	// previous output message if it has been specified
+	if *previousPath != "" {
+		data, err := readInput(*previousPath)
+		if err == nil {
+			err = json.Unmarshal(data, &om)
+		}
+		if err != nil {
+			return nil, output.NewError(output.ERRPREVIOUSMAPPINGUNAVAILABLE, "Failed to get previous output: "+err.Error(), nil)
+		}
+
+		om.RedactSecrets()
+	}
+
+	return mapper.MapCreation(p, &om)
+}
+
+// loadPayload builds a payload from the definition and datacenter files
+func loadPayload() (*definition.Payload, error) {
+	var p definition.Payload
+
+	if *variablesPath != "" {
+		data, err := readYAML(*variablesPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(data, &p.Variables); err != nil {
+			return nil, errors.New("failed to parse variables: " + err.Error())
+		}
+	}
+
+	data, err := readYAML(*definitionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = definition.ResolveVariables(data, p.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := definition.FromJSON(data)
+	if err != nil {
+		return nil, errors.New("failed to parse definition: " + err.Error())
+	}
+
+	data, err = readYAML(*datacenterPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &p.Datacenter); err != nil {
+		return nil, errors.New("failed to parse datacenter: " + err.Error())
+	}
+
+	p.ServiceID = *serviceID
+	p.Client.Name = *clientName
+	p.Service = *d
+	p.Service.DatacenterDetails = p.Datacenter
+
+	return &p, nil
+}
+
+// readYAML reads a yaml or json input, returning it as json
+func readYAML(path string) ([]byte, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.YAMLToJSON(data)
+}
+
+// readInput reads a file, or stdin if the path is -
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+
+	return ioutil.ReadFile(path)
+}
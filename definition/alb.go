@@ -0,0 +1,229 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// ALBHealthCheck ...
+type ALBHealthCheck struct {
+	Path               string `json:"path"`
+	Port               int    `json:"port"`
+	Protocol           string `json:"protocol"`
+	Interval           int    `json:"interval"`
+	Timeout            int    `json:"timeout"`
+	HealthyThreshold   int    `json:"healthy_threshold"`
+	UnhealthyThreshold int    `json:"unhealthy_threshold"`
+	Matcher            string `json:"matcher"`
+}
+
+// ALBTargetGroup ...
+type ALBTargetGroup struct {
+	Name        string         `json:"name"`
+	Port        int            `json:"port"`
+	Protocol    string         `json:"protocol"`
+	Instances   []string       `json:"instances"`
+	HealthCheck ALBHealthCheck `json:"health_check"`
+}
+
+// ALBListenerRule ...
+type ALBListenerRule struct {
+	Path        string `json:"path"`
+	Host        string `json:"host"`
+	Priority    int    `json:"priority"`
+	TargetGroup string `json:"target_group"`
+}
+
+// ALBListener ...
+type ALBListener struct {
+	Port               int               `json:"port"`
+	Protocol           string            `json:"protocol"`
+	SSLCert            string            `json:"ssl_cert"`
+	DefaultTargetGroup string            `json:"default_target_group"`
+	Rules              []ALBListenerRule `json:"rules"`
+}
+
+// ALB ...
+type ALB struct {
+	Name           string           `json:"name"`
+	Private        bool             `json:"private"`
+	Subnets        []string         `json:"networks"`
+	SecurityGroups []string         `json:"security_groups"`
+	TargetGroups   []ALBTargetGroup `json:"target_groups"`
+	Listeners      []ALBListener    `json:"listeners"`
+}
+
+// Validate checks if an application loadbalancer is valid, returning every
+// field that is not valid
+func (a *ALB) Validate(networks []Network) error {
+	var errs ValidationErrors
+
+	if a.Name == "" {
+		errs.addField(fieldError("name", "ALB name should not be null"))
+	} else if utf8.RuneCountInString(a.Name) > AWSMAXNAME {
+		// Check if alb name is > 50 characters
+		errs.addField(fieldErrorf("name", "ALB name can't be greater than %d characters", AWSMAXNAME))
+	}
+
+	if len(a.Subnets) < 2 && countSubnets(networks, a.Subnets) < 2 {
+		errs.addField(fieldError("networks", "ALB must specify at least two networks"))
+	}
+
+	for _, nw := range a.Subnets {
+		if isNetwork(networks, nw) != true {
+			errs.addField(fieldErrorf("networks", "ALB network (%s) does not exist", nw))
+		}
+		for _, n := range networks {
+			if nw == n.Name && n.Public != true && a.Private != true {
+				errs.addField(fieldErrorf("networks", "ALB network (%s) is not a public network", nw))
+			}
+		}
+	}
+
+	if len(a.TargetGroups) < 1 {
+		errs.addField(fieldError("target_groups", "ALB must specify at least one target group"))
+	}
+
+	for x, tg := range a.TargetGroups {
+		err := tg.Validate()
+		if err != nil {
+			errs.addField(withField(fmt.Sprintf("target_groups[%d]", x), err))
+		}
+
+		for _, otg := range a.TargetGroups[x+1:] {
+			if tg.Name == otg.Name {
+				errs.addField(fieldErrorf(fmt.Sprintf("target_groups[%d].name", x), "ALB target group (%s) is defined more than once", tg.Name))
+			}
+		}
+	}
+
+	if len(a.Listeners) < 1 {
+		errs.addField(fieldError("listeners", "ALB must specify at least one listener"))
+	}
+
+	for x, listener := range a.Listeners {
+		err := listener.Validate(a.TargetGroups)
+		if err != nil {
+			errs.addField(withField(fmt.Sprintf("listeners[%d]", x), err))
+		}
+	}
+
+	return errs.errorOrNil()
+}
+
+// FindTargetGroup returns a target group matched by name
+func (a *ALB) FindTargetGroup(name string) *ALBTargetGroup {
+	for _, tg := range a.TargetGroups {
+		if tg.Name == name {
+			return &tg
+		}
+	}
+	return nil
+}
+
+// Validate checks if a target group is valid
+func (tg *ALBTargetGroup) Validate() error {
+	var errs ValidationErrors
+
+	if tg.Name == "" {
+		errs.addField(fieldError("name", "ALB target group name should not be null"))
+	}
+
+	if tg.Port < 1 || tg.Port > 65535 {
+		errs.addField(fieldErrorf("port", "ALB target group port (%d) is out of range [1 - 65535]", tg.Port))
+	}
+
+	if tg.Protocol != "http" && tg.Protocol != "https" {
+		errs.addField(fieldError("protocol", "ALB target group protocol must be one of http or https"))
+	}
+
+	hc := tg.HealthCheck
+
+	if hc.Protocol != "" && hc.Protocol != "http" && hc.Protocol != "https" {
+		errs.addField(fieldError("health_check.protocol", "ALB health check protocol must be one of http or https"))
+	}
+
+	if hc.Port < 0 || hc.Port > 65535 {
+		errs.addField(fieldErrorf("health_check.port", "ALB health check port (%d) is out of range [1 - 65535]", hc.Port))
+	}
+
+	if hc.Interval != 0 && (hc.Interval < 5 || hc.Interval > 300) {
+		errs.addField(fieldError("health_check.interval", "ALB health check interval should be between 5 and 300 seconds"))
+	}
+
+	if hc.Timeout != 0 && (hc.Timeout < 2 || hc.Timeout > 120) {
+		errs.addField(fieldError("health_check.timeout", "ALB health check timeout should be between 2 and 120 seconds"))
+	}
+
+	if hc.Interval != 0 && hc.Timeout >= hc.Interval {
+		errs.addField(fieldError("health_check.timeout", "ALB health check timeout must be smaller than the interval"))
+	}
+
+	if hc.HealthyThreshold != 0 && (hc.HealthyThreshold < 2 || hc.HealthyThreshold > 10) {
+		errs.addField(fieldError("health_check.healthy_threshold", "ALB health check healthy threshold should be between 2 and 10"))
+	}
+
+	if hc.UnhealthyThreshold != 0 && (hc.UnhealthyThreshold < 2 || hc.UnhealthyThreshold > 10) {
+		errs.addField(fieldError("health_check.unhealthy_threshold", "ALB health check unhealthy threshold should be between 2 and 10"))
+	}
+
+	return errs.errorOrNil()
+}
+
+// Validate checks if a listener is valid
+func (l *ALBListener) Validate(targetgroups []ALBTargetGroup) error {
+	var errs ValidationErrors
+
+	if l.Port < 1 || l.Port > 65535 {
+		errs.addField(fieldErrorf("port", "ALB listener port (%d) is out of range [1 - 65535]", l.Port))
+	}
+
+	if l.Protocol != "http" && l.Protocol != "https" {
+		errs.addField(fieldError("protocol", "ALB listener protocol must be one of http or https"))
+	}
+
+	if l.Protocol == "https" && l.SSLCert == "" {
+		errs.addField(fieldError("ssl_cert", "ALB listener must specify an ssl cert when protocol is https"))
+	}
+
+	if l.DefaultTargetGroup == "" {
+		errs.addField(fieldError("default_target_group", "ALB listener default target group should not be null"))
+	} else if isTargetGroup(targetgroups, l.DefaultTargetGroup) != true {
+		errs.addField(fieldErrorf("default_target_group", "ALB listener target group (%s) does not exist", l.DefaultTargetGroup))
+	}
+
+	for x, rule := range l.Rules {
+		if rule.Path == "" && rule.Host == "" {
+			errs.addField(fieldError(fmt.Sprintf("rules[%d]", x), "ALB listener rule must specify a path or a host"))
+		}
+
+		if rule.Priority < 1 || rule.Priority > 50000 {
+			errs.addField(fieldError(fmt.Sprintf("rules[%d].priority", x), "ALB listener rule priority should be between 1 and 50000"))
+		}
+
+		for _, or := range l.Rules[x+1:] {
+			if rule.Priority == or.Priority {
+				errs.addField(fieldErrorf(fmt.Sprintf("rules[%d].priority", x), "ALB listener rule priority (%d) is used more than once", rule.Priority))
+			}
+		}
+
+		if isTargetGroup(targetgroups, rule.TargetGroup) != true {
+			errs.addField(fieldErrorf(fmt.Sprintf("rules[%d].target_group", x), "ALB listener target group (%s) does not exist", rule.TargetGroup))
+		}
+	}
+
+	return errs.errorOrNil()
+}
+
+func isTargetGroup(targetgroups []ALBTargetGroup, name string) bool {
+	for _, tg := range targetgroups {
+		if tg.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestALBValidate(t *testing.T) {
+	Convey("Given an alb", t, func() {
+		n := []Network{
+			Network{Name: "web-a", Subnet: "10.0.0.0/24", Public: true},
+			Network{Name: "web-b", Subnet: "10.0.1.0/24", Public: true},
+		}
+		a := ALB{
+			Name:    "foo",
+			Subnets: []string{"web-a", "web-b"},
+			TargetGroups: []ALBTargetGroup{
+				ALBTargetGroup{Name: "web", Port: 80, Protocol: "http", HealthCheck: ALBHealthCheck{Path: "/health", Interval: 30, Timeout: 5}},
+				ALBTargetGroup{Name: "api", Port: 8080, Protocol: "http"},
+			},
+			Listeners: []ALBListener{
+				ALBListener{
+					Port:               443,
+					Protocol:           "https",
+					SSLCert:            "cert",
+					DefaultTargetGroup: "web",
+					Rules: []ALBListenerRule{
+						ALBListenerRule{Path: "/api/*", Priority: 1, TargetGroup: "api"},
+						ALBListenerRule{Host: "api.example.com", Priority: 2, TargetGroup: "api"},
+					},
+				},
+			},
+		}
+
+		Convey("With a valid configuration", func() {
+			Convey("When validating the alb", func() {
+				err := a.Validate(n)
+				Convey("Then it should not return an error", func() {
+					So(err, ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("With a single network", func() {
+			a.Subnets = []string{"web-a"}
+			Convey("When validating the alb", func() {
+				err := a.Validate(n)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "ALB must specify at least two networks")
+				})
+			})
+		})
+
+		Convey("With a private network on a public alb", func() {
+			n[1].Public = false
+			Convey("When validating the alb", func() {
+				err := a.Validate(n)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "ALB network (web-b) is not a public network")
+				})
+			})
+		})
+
+		Convey("With an https listener without a certificate", func() {
+			a.Listeners[0].SSLCert = ""
+			Convey("When validating the alb", func() {
+				err := a.Validate(n)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Field, ShouldEqual, "listeners[0].ssl_cert")
+				})
+			})
+		})
+
+		Convey("With a rule without a path or host", func() {
+			a.Listeners[0].Rules[1].Host = ""
+			Convey("When validating the alb", func() {
+				err := a.Validate(n)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "ALB listener rule must specify a path or a host")
+				})
+			})
+		})
+
+		Convey("With duplicate rule priorities", func() {
+			a.Listeners[0].Rules[1].Priority = 1
+			Convey("When validating the alb", func() {
+				err := a.Validate(n)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "ALB listener rule priority (1) is used more than once")
+				})
+			})
+		})
+
+		Convey("With a rule forwarding to an unknown target group", func() {
+			a.Listeners[0].Rules[0].TargetGroup = "unknown"
+			Convey("When validating the alb", func() {
+				err := a.Validate(n)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Field, ShouldEqual, "listeners[0].rules[0].target_group")
+				})
+			})
+		})
+
+		Convey("With an invalid health check timeout", func() {
+			a.TargetGroups[0].HealthCheck.Timeout = 30
+			Convey("When validating the alb", func() {
+				err := a.Validate(n)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "ALB health check timeout must be smaller than the interval")
+				})
+			})
+		})
+
+		Convey("With duplicate target groups", func() {
+			a.TargetGroups[1].Name = "web"
+			Convey("When validating the alb", func() {
+				err := a.Validate(n)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Message, ShouldEqual, "ALB target group (web) is defined more than once")
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,99 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import "unicode/utf8"
+
+// LaunchConfiguration ...
+type LaunchConfiguration struct {
+	Image          string   `json:"image"`
+	Type           string   `json:"type"`
+	KeyPair        string   `json:"key_pair"`
+	SecurityGroups []string `json:"security_groups"`
+	UserData       string   `json:"user_data"`
+}
+
+// AutoscalingGroup ...
+type AutoscalingGroup struct {
+	Name                string              `json:"name"`
+	LaunchConfiguration LaunchConfiguration `json:"launch_configuration"`
+	MinSize             int                 `json:"min_size"`
+	MaxSize             int                 `json:"max_size"`
+	DesiredCapacity     int                 `json:"desired_capacity"`
+	Networks            []string            `json:"networks"`
+	ELBs                []string            `json:"loadbalancers"`
+}
+
+// Validate checks if an autoscaling group is valid
+func (a *AutoscalingGroup) Validate(networks []Network, securitygroups []SecurityGroup, elbs []ELB) error {
+	var errs ValidationErrors
+
+	if a.Name == "" {
+		errs.addField(fieldError("name", "Autoscaling group name should not be null"))
+	} else if utf8.RuneCountInString(a.Name) > AWSMAXNAME {
+		errs.addField(fieldErrorf("name", "Autoscaling group name can't be greater than %d characters", AWSMAXNAME))
+	}
+
+	if a.LaunchConfiguration.Image == "" {
+		errs.addField(fieldError("launch_configuration.image", "Autoscaling group image should not be null"))
+	}
+
+	if a.LaunchConfiguration.Type == "" {
+		errs.addField(fieldError("launch_configuration.type", "Autoscaling group instance type should not be null"))
+	}
+
+	if err := validateSecret(a.LaunchConfiguration.UserData); err != nil {
+		errs.addField(fieldErrorf("launch_configuration.user_data", "Autoscaling group user data secret reference is not valid: %s", err.Error()))
+	}
+
+	for _, sg := range a.LaunchConfiguration.SecurityGroups {
+		if isSecurityGroup(securitygroups, sg) != true {
+			errs.addField(fieldErrorf("launch_configuration.security_groups", "Autoscaling group security group (%s) does not exist", sg))
+		}
+	}
+
+	if a.MinSize < 0 {
+		errs.addField(fieldError("min_size", "Autoscaling group min size should not be < 0"))
+	}
+
+	if a.MaxSize < 1 {
+		errs.addField(fieldError("max_size", "Autoscaling group max size should not be < 1"))
+	}
+
+	if a.MaxSize < a.MinSize {
+		errs.addField(fieldError("max_size", "Autoscaling group max size should not be smaller than its min size"))
+	}
+
+	if a.DesiredCapacity < a.MinSize || a.DesiredCapacity > a.MaxSize {
+		errs.addField(fieldError("desired_capacity", "Autoscaling group desired capacity should be between its min and max size"))
+	}
+
+	if len(a.Networks) < 1 {
+		errs.addField(fieldError("networks", "Autoscaling group must specify at least one network"))
+	}
+
+	for _, nw := range a.Networks {
+		if isNetwork(networks, nw) != true {
+			errs.addField(fieldErrorf("networks", "Autoscaling group network (%s) does not exist", nw))
+		}
+	}
+
+	for _, elb := range a.ELBs {
+		if isELB(elbs, elb) != true {
+			errs.addField(fieldErrorf("loadbalancers", "Autoscaling group loadbalancer (%s) does not exist", elb))
+		}
+	}
+
+	return errs.errorOrNil()
+}
+
+func isELB(elbs []ELB, name string) bool {
+	for _, elb := range elbs {
+		if elb.Name == name {
+			return true
+		}
+	}
+	return false
+}
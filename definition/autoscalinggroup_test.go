@@ -0,0 +1,122 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAutoscalingGroupValidate(t *testing.T) {
+	Convey("Given an autoscaling group", t, func() {
+		n := []Network{Network{Name: "web", Subnet: "10.0.0.0/24"}}
+		sg := []SecurityGroup{SecurityGroup{Name: "web-sg"}}
+		e := []ELB{ELB{Name: "web-lb"}}
+		a := AutoscalingGroup{
+			Name: "web",
+			LaunchConfiguration: LaunchConfiguration{
+				Image:          "ami-000000",
+				Type:           "t2.micro",
+				SecurityGroups: []string{"web-sg"},
+			},
+			MinSize:         1,
+			MaxSize:         3,
+			DesiredCapacity: 2,
+			Networks:        []string{"web"},
+			ELBs:            []string{"web-lb"},
+		}
+
+		Convey("With multiple invalid fields", func() {
+			a.LaunchConfiguration.Image = ""
+			a.Networks = nil
+			Convey("When validating the autoscaling group", func() {
+				err := a.Validate(n, sg, e)
+				Convey("Then it should return an error for every invalid field", func() {
+					So(err, ShouldNotBeNil)
+					errs := err.(ValidationErrors)
+					So(len(errs), ShouldEqual, 2)
+					So(errs[0].Field, ShouldEqual, "launch_configuration.image")
+					So(errs[1].Field, ShouldEqual, "networks")
+				})
+			})
+		})
+
+		Convey("With a valid configuration", func() {
+			Convey("When validating the autoscaling group", func() {
+				err := a.Validate(n, sg, e)
+				Convey("Then it should not return an error", func() {
+					So(err, ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("With no image", func() {
+			a.LaunchConfiguration.Image = ""
+			Convey("When validating the autoscaling group", func() {
+				err := a.Validate(n, sg, e)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Field, ShouldEqual, "launch_configuration.image")
+				})
+			})
+		})
+
+		Convey("With a max size smaller than the min size", func() {
+			a.MinSize = 4
+			Convey("When validating the autoscaling group", func() {
+				err := a.Validate(n, sg, e)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Message, ShouldEqual, "Autoscaling group max size should not be smaller than its min size")
+				})
+			})
+		})
+
+		Convey("With a desired capacity outside of its bounds", func() {
+			a.DesiredCapacity = 4
+			Convey("When validating the autoscaling group", func() {
+				err := a.Validate(n, sg, e)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Field, ShouldEqual, "desired_capacity")
+				})
+			})
+		})
+
+		Convey("With an unknown network", func() {
+			a.Networks = []string{"db"}
+			Convey("When validating the autoscaling group", func() {
+				err := a.Validate(n, sg, e)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "Autoscaling group network (db) does not exist")
+				})
+			})
+		})
+
+		Convey("With an unknown security group", func() {
+			a.LaunchConfiguration.SecurityGroups = []string{"db-sg"}
+			Convey("When validating the autoscaling group", func() {
+				err := a.Validate(n, sg, e)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "Autoscaling group security group (db-sg) does not exist")
+				})
+			})
+		})
+
+		Convey("With an unknown loadbalancer", func() {
+			a.ELBs = []string{"db-lb"}
+			Convey("When validating the autoscaling group", func() {
+				err := a.Validate(n, sg, e)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Field, ShouldEqual, "loadbalancers")
+				})
+			})
+		})
+	})
+}
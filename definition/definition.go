@@ -6,7 +6,6 @@ package definition
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net"
 	"unicode/utf8"
@@ -14,21 +13,26 @@ import (
 
 // Definition ...
 type Definition struct {
-	Name              string          `json:"name"`
-	Datacenter        string          `json:"datacenter"`
-	VpcID             string          `json:"vpc_id"`
-	VpcSubnet         string          `json:"vpc_subnet,omitempty"`
-	Networks          []Network       `json:"networks,omitempty"`
-	Instances         []Instance      `json:"instances,omitempty"`
-	SecurityGroups    []SecurityGroup `json:"security_groups,omitempty"`
-	ELBs              []ELB           `json:"loadbalancers,omitempty"`
-	S3Buckets         []S3            `json:"s3_buckets,omitempty"`
-	Route53Zones      []Route53Zone   `json:"route53_zones,omitempty"`
-	RDSClusters       []RDSCluster    `json:"rds_clusters,omitempty"`
-	RDSInstances      []RDSInstance   `json:"rds_instances,omitempty"`
-	NatGateways       []NatGateway    `json:"nat_gateways,omitempty"`
-	EBSVolumes        []EBSVolume     `json:"ebs_volumes,omitempty"`
-	DatacenterDetails Datacenter      `json:"-"`
+	Name              string              `json:"name"`
+	Datacenter        string              `json:"datacenter"`
+	VpcID             string              `json:"vpc_id"`
+	VpcSubnet         string              `json:"vpc_subnet,omitempty"`
+	Networks          []Network           `json:"networks,omitempty"`
+	Instances         []Instance          `json:"instances,omitempty"`
+	AutoscalingGroups []AutoscalingGroup  `json:"autoscaling_groups,omitempty"`
+	SecurityGroups    []SecurityGroup     `json:"security_groups,omitempty"`
+	ELBs              []ELB               `json:"loadbalancers,omitempty"`
+	ALBs              []ALB               `json:"application_loadbalancers,omitempty"`
+	S3Buckets         []S3                `json:"s3_buckets,omitempty"`
+	Route53Zones      []Route53Zone       `json:"route53_zones,omitempty"`
+	RDSClusters       []RDSCluster        `json:"rds_clusters,omitempty"`
+	RDSInstances      []RDSInstance       `json:"rds_instances,omitempty"`
+	NatGateways       []NatGateway        `json:"nat_gateways,omitempty"`
+	EBSVolumes        []EBSVolume         `json:"ebs_volumes,omitempty"`
+	IAMRoles          []IAMRole           `json:"iam_roles,omitempty"`
+	Variables         map[string]Variable `json:"variables,omitempty"`
+	DatacenterDetails Datacenter          `json:"-"`
+	PreviousIPs       map[string]net.IP   `json:"-"`
 }
 
 // New returns a new Definition
@@ -44,7 +48,7 @@ func New() *Definition {
 func FromJSON(data []byte) (*Definition, error) {
 	var d Definition
 
-	err := json.Unmarshal(data, d)
+	err := json.Unmarshal(data, &d)
 	if err != nil {
 		return nil, err
 	}
@@ -55,16 +59,20 @@ func FromJSON(data []byte) (*Definition, error) {
 // ValidateVPC checks if vpc is valid
 func (d *Definition) validateVPC() error {
 	if d.VpcID == "" && d.VpcSubnet == "" {
-		return errors.New("Please specify either the vpc_id of an existing vpc, or specify which vpc_subnet you want to use when creating a vpc")
+		return fieldError("vpc_subnet", "Please specify either the vpc_id of an existing vpc, or specify which vpc_subnet you want to use when creating a vpc")
 	}
 
 	if d.VpcID != "" && d.VpcSubnet == "" {
 		return nil
 	}
 
-	_, _, err := net.ParseCIDR(d.VpcSubnet)
+	_, vpc, err := net.ParseCIDR(d.VpcSubnet)
 	if err != nil {
-		return errors.New("VPC subnet is not valid")
+		return fieldError("vpc_subnet", "VPC subnet is not valid")
+	}
+
+	if vpc.IP.To4() == nil {
+		return fieldErrorf("vpc_subnet", "VPC subnet (%s) must be an IPv4 range", d.VpcSubnet)
 	}
 
 	return nil
@@ -74,138 +82,283 @@ func (d *Definition) validateVPC() error {
 func (d *Definition) validateName() error {
 	// Check if service name is null
 	if d.Name == "" {
-		return errors.New("Service name should not be null")
+		return fieldError("name", "Service name should not be null")
 	}
 
 	// Check if service name is > 50 characters
 	if utf8.RuneCountInString(d.Name) > 50 {
-		return fmt.Errorf("Datacenter name can't be greater than %d characters", AWSMAXNAME)
+		return fieldErrorf("name", "Datacenter name can't be greater than %d characters", AWSMAXNAME)
 	}
 	return nil
 }
 
 func (d *Definition) validateDatacenter() error {
 	if d.Datacenter == "" {
-		return errors.New("Datacenter not specified")
+		return fieldError("datacenter", "Datacenter not specified")
 	}
 	return nil
 }
 
-// Validate the definition
+// validateNatZones checks a private network can be routed through a nat
+// gateway in each of its availability zones. A nat gateway whose public
+// network spans multiple zones has a gateway in each of them, so the network
+// must only use those zones. A single gateway routes every zone
+func (d *Definition) validateNatZones(n Network) error {
+	ng := d.FindNatGateway(n.NatGateway)
+	if n.Public || ng == nil {
+		return nil
+	}
+
+	pn := d.FindNetwork(ng.PublicNetwork)
+	if pn == nil || len(pn.AvailabilityZones) < 2 {
+		return nil
+	}
+
+	var errs ValidationErrors
+
+	for _, az := range n.Zones() {
+		if isOneOf(pn.AvailabilityZones, az) {
+			continue
+		}
+
+		if az == "" {
+			errs.addField(fieldErrorf("availability_zone", "Network should specify one of the availability zones of its nat gateway's public network (%s)", pn.Name))
+			continue
+		}
+
+		errs.addField(fieldErrorf("availability_zones", "Network availability zone (%s) is not used by its nat gateway's public network (%s)", az, pn.Name))
+	}
+
+	return errs.errorOrNil()
+}
+
+// Validate the definition, returning every validation error found as ValidationErrors
 func (d *Definition) Validate() error {
+	var errs ValidationErrors
+
 	// Validate Name
-	if err := d.validateName(); err != nil {
-		return err
-	}
+	errs.add("service", d.Name, d.validateName())
 
 	// Validate Datacenter
-	if err := d.validateDatacenter(); err != nil {
-		return err
-	}
+	errs.add("service", d.Name, d.validateDatacenter())
 
 	// Validate VPC
-	if err := d.validateVPC(); err != nil {
-		return err
-	}
+	errs.add("service", d.Name, d.validateVPC())
 
 	// Validate Networks
 	for _, n := range d.Networks {
-		if err := n.Validate(&d.DatacenterDetails); err != nil {
-			return err
-		}
+		errs.add("network", n.Name, n.Validate(&d.DatacenterDetails))
+		errs.add("network", n.Name, d.validateNatZones(n))
 	}
 
+	errs = append(errs, d.validateSubnets()...)
+
 	// Validate Instances
 	for _, i := range d.Instances {
 		nw := d.FindNetwork(i.Network)
 
-		if err := i.Validate(nw, d.EBSVolumes); err != nil {
-			return err
+		errs.add("instance", i.Name, i.Validate(nw, d.EBSVolumes))
+
+		if i.IAMProfile != "" && d.FindIAMRole(i.IAMProfile) == nil {
+			errs.add("instance", i.Name, fieldErrorf("iam_profile", "Instance IAM profile (%s) is not valid", i.IAMProfile))
 		}
 	}
 
+	// Validate instance IP allocation across instance groups
+	_, ipErrs := d.InstanceIPs()
+	errs = append(errs, ipErrs...)
+
+	// Validate Autoscaling Groups
+	for _, asg := range d.AutoscalingGroups {
+		errs.add("autoscaling_group", asg.Name, asg.Validate(d.Networks, d.SecurityGroups, d.ELBs))
+	}
+
 	// Validate Security Groups
 	for _, sg := range d.SecurityGroups {
-		if err := sg.Validate(d.Networks); err != nil {
-			return err
+		errs.add("security_group", sg.Name, sg.Validate(d.Networks))
+
+		for x, rule := range sg.Ingress {
+			if rule.SecurityGroup != "" && d.FindSecurityGroup(rule.SecurityGroup) == nil {
+				errs.add("security_group", sg.Name, fieldErrorf(fmt.Sprintf("ingress[%d].security_group", x), "Security Group rule security group (%s) is not valid", rule.SecurityGroup))
+			}
+		}
+		for x, rule := range sg.Egress {
+			if rule.SecurityGroup != "" && d.FindSecurityGroup(rule.SecurityGroup) == nil {
+				errs.add("security_group", sg.Name, fieldErrorf(fmt.Sprintf("egress[%d].security_group", x), "Security Group rule security group (%s) is not valid", rule.SecurityGroup))
+			}
 		}
 	}
 
 	// Validate Nat Gateways
 	for _, ng := range d.NatGateways {
-		if err := ng.Validate(d.Networks); err != nil {
-			return err
-		}
+		errs.add("nat_gateway", ng.Name, ng.Validate(d.Networks))
 	}
 
 	// Validate ELB's
 	for _, lb := range d.ELBs {
-		if err := lb.Validate(d.Networks); err != nil {
-			return err
-		}
+		errs.add("loadbalancer", lb.Name, lb.Validate(d.Networks))
+
 		for _, instance := range lb.Instances {
 			if d.FindInstance(instance) == nil {
-				return fmt.Errorf("ELB Instance (%s) is not valid", instance)
+				errs.add("loadbalancer", lb.Name, fieldErrorf("instances", "ELB Instance (%s) is not valid", instance))
 			}
 		}
 		for _, sg := range lb.SecurityGroups {
 			if d.FindSecurityGroup(sg) == nil {
-				return fmt.Errorf("ELB Security Group (%s) is not valid", sg)
+				errs.add("loadbalancer", lb.Name, fieldErrorf("security_groups", "ELB Security Group (%s) is not valid", sg))
+			}
+		}
+	}
+
+	// Validate ALB's
+	for _, lb := range d.ALBs {
+		errs.add("application_loadbalancer", lb.Name, lb.Validate(d.Networks))
+
+		for x, tg := range lb.TargetGroups {
+			for _, instance := range tg.Instances {
+				if d.FindInstance(instance) == nil {
+					errs.add("application_loadbalancer", lb.Name, fieldErrorf(fmt.Sprintf("target_groups[%d].instances", x), "ALB target group instance (%s) is not valid", instance))
+				}
+			}
+		}
+		for _, sg := range lb.SecurityGroups {
+			if d.FindSecurityGroup(sg) == nil {
+				errs.add("application_loadbalancer", lb.Name, fieldErrorf("security_groups", "ALB Security Group (%s) is not valid", sg))
 			}
 		}
 	}
 
 	// Validate S3 Buckets
 	for _, s3bucket := range d.S3Buckets {
-		err := s3bucket.Validate()
-		if err != nil {
-			return err
-		}
+		errs.add("s3_bucket", s3bucket.Name, s3bucket.Validate())
 	}
 
 	// Validate Route53
 	for _, r53 := range d.Route53Zones {
-		err := r53.Validate()
-		if err != nil {
-			return err
-		}
+		errs.add("route53_zone", r53.Name, r53.Validate())
 	}
 
 	// Validate RDS Clusters
 	for _, rdsc := range d.RDSClusters {
-		err := rdsc.Validate(d.Networks, d.SecurityGroups)
-		if err != nil {
-			return err
-		}
+		errs.add("rds_cluster", rdsc.Name, rdsc.Validate(d.Networks, d.SecurityGroups))
 	}
 
 	// Validate RDS Instances
 	for _, rdsi := range d.RDSInstances {
-		err := rdsi.Validate(d.Networks, d.SecurityGroups, d.RDSClusters)
-		if err != nil {
-			return err
-		}
+		errs.add("rds_instance", rdsi.Name, rdsi.Validate(d.Networks, d.SecurityGroups, d.RDSClusters))
 	}
 
 	// validate EBS Volumes
 	for _, vol := range d.EBSVolumes {
-		err := vol.Validate()
-		if err != nil {
-			return err
-		}
+		errs.add("ebs_volume", vol.Name, vol.Validate())
+	}
+
+	// Validate IAM Roles
+	for _, role := range d.IAMRoles {
+		errs.add("iam_role", role.Name, role.Validate())
 	}
 
 	if hasDuplicateNetworks(d.Networks) {
-		return errors.New("Duplicate network names found")
+		errs.add("network", "", fieldError("name", "Duplicate network names found"))
 	}
 
 	if hasDuplicateInstance(d.Instances) {
-		return errors.New("Duplicate instance names found")
+		errs.add("instance", "", fieldError("name", "Duplicate instance names found"))
+	}
+
+	if len(errs) > 0 {
+		return errs
 	}
 
 	return nil
 }
 
+// ValidateChanges checks that every component can be changed from how it
+// was defined on a previous build
+func (d *Definition) ValidateChanges(previous *Definition) error {
+	var errs ValidationErrors
+
+	// Validate Networks
+	for _, n := range d.Networks {
+		if on := previous.FindNetwork(n.Name); on != nil {
+			errs.add("network", n.Name, n.ValidateChange(on, d.isNetworkUsed(n.Name)))
+		}
+	}
+
+	// Validate EBS Volumes
+	for _, vol := range d.EBSVolumes {
+		if ov := previous.FindEBSVolume(vol.Name); ov != nil {
+			errs.add("ebs_volume", vol.Name, vol.ValidateChange(ov, d.isVolumeAttached(vol.Name)))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// isNetworkUsed returns true if any component is placed on a network
+func (d *Definition) isNetworkUsed(name string) bool {
+	for _, i := range d.Instances {
+		if i.Network == name {
+			return true
+		}
+	}
+
+	for _, asg := range d.AutoscalingGroups {
+		if isOneOf(asg.Networks, name) {
+			return true
+		}
+	}
+
+	for _, elb := range d.ELBs {
+		if isOneOf(elb.Subnets, name) {
+			return true
+		}
+	}
+
+	for _, alb := range d.ALBs {
+		if isOneOf(alb.Subnets, name) {
+			return true
+		}
+	}
+
+	for _, r := range d.RDSClusters {
+		if isOneOf(r.Networks, name) {
+			return true
+		}
+	}
+
+	for _, r := range d.RDSInstances {
+		if isOneOf(r.Networks, name) {
+			return true
+		}
+	}
+
+	for _, ng := range d.NatGateways {
+		if ng.PublicNetwork == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isVolumeAttached returns true if a volume is attached to any instance
+func (d *Definition) isVolumeAttached(name string) bool {
+	for _, i := range d.Instances {
+		for _, v := range i.Volumes {
+			if v.Volume == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // GeneratedName returns the generated service name
 func (d *Definition) GeneratedName() string {
 	return d.Datacenter + "-" + d.Name + "-"
@@ -221,6 +374,16 @@ func (d *Definition) FindNetwork(name string) *Network {
 	return nil
 }
 
+// FindNatGateway returns a nat gateway matched by name
+func (d *Definition) FindNatGateway(name string) *NatGateway {
+	for _, ng := range d.NatGateways {
+		if ng.Name == name {
+			return &ng
+		}
+	}
+	return nil
+}
+
 // FindInstance returns a instance matched by name
 func (d *Definition) FindInstance(name string) *Instance {
 	for _, instance := range d.Instances {
@@ -251,3 +414,23 @@ func (d *Definition) FindRDSCluster(name string) *RDSCluster {
 	return nil
 
 }
+
+// FindIAMRole returns an iam role matched by name
+func (d *Definition) FindIAMRole(name string) *IAMRole {
+	for _, r := range d.IAMRoles {
+		if r.Name == name {
+			return &r
+		}
+	}
+	return nil
+}
+
+// FindEBSVolume returns an ebs volume matched by name
+func (d *Definition) FindEBSVolume(name string) *EBSVolume {
+	for _, v := range d.EBSVolumes {
+		if v.Name == name {
+			return &v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDefinitionValidate(t *testing.T) {
+	Convey("Given a definition", t, func() {
+		d := Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+			VpcSubnet:  "10.0.0.0/16",
+			Networks: []Network{
+				{Name: "web", Subnet: "10.0.0.0/24"},
+			},
+			Instances: []Instance{
+				{Name: "web", Type: "t2.micro", Image: "ami-000000", Count: 1, Network: "web", StartIP: []byte{10, 0, 0, 10}},
+			},
+			SecurityGroups: []SecurityGroup{
+				{Name: "web-sg", Ingress: []SecurityGroupRule{{IP: "10.0.0.0/24", FromPort: "80", ToPort: "80", Protocol: "tcp"}}},
+			},
+		}
+
+		Convey("When it is valid", func() {
+			err := d.Validate()
+
+			Convey("Then it should not return an error", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When multiple components are invalid", func() {
+			d.Networks[0].Subnet = "invalid"
+			d.Instances[0].Type = ""
+			d.SecurityGroups[0].Ingress[0].FromPort = "99999"
+
+			err := d.Validate()
+
+			Convey("Then it should return every error", func() {
+				So(err, ShouldNotBeNil)
+
+				errs, ok := err.(ValidationErrors)
+				So(ok, ShouldBeTrue)
+				So(len(errs), ShouldEqual, 4)
+				So(errs[0], ShouldResemble, ValidationError{Type: "network", Name: "web", Field: "subnet", Message: "Network CIDR is not valid"})
+				So(errs[1], ShouldResemble, ValidationError{Type: "instance", Name: "web", Field: "type", Message: "Instance type should not be null"})
+				So(errs[2], ShouldResemble, ValidationError{Type: "instance", Name: "web", Field: "network", Message: "Could not process network"})
+				So(errs[3], ShouldResemble, ValidationError{Type: "security_group", Name: "web-sg", Field: "ingress[0].from_port", Message: "Security Group From Port (99999) is out of range [0 - 65535]"})
+			})
+		})
+
+		Convey("When a rule references an unknown security group", func() {
+			d.SecurityGroups[0].Ingress = append(d.SecurityGroups[0].Ingress, SecurityGroupRule{SecurityGroup: "db-sg", FromPort: "5432", ToPort: "5432", Protocol: "tcp"})
+
+			err := d.Validate()
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+
+				errs, ok := err.(ValidationErrors)
+				So(ok, ShouldBeTrue)
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0], ShouldResemble, ValidationError{Type: "security_group", Name: "web-sg", Field: "ingress[1].security_group", Message: "Security Group rule security group (db-sg) is not valid"})
+			})
+		})
+
+		Convey("When a rule references a known security group", func() {
+			d.SecurityGroups[0].Ingress = append(d.SecurityGroups[0].Ingress, SecurityGroupRule{SecurityGroup: "web-sg", FromPort: "5432", ToPort: "5432", Protocol: "tcp"})
+
+			err := d.Validate()
+
+			Convey("Then it should not return an error", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When instance groups are allocated overlapping ips", func() {
+			d.Instances = append(d.Instances, Instance{Name: "app", Type: "t2.micro", Image: "ami-000000", Count: 2, Network: "web", StartIP: []byte{10, 0, 0, 9}})
+
+			err := d.Validate()
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+
+				errs, ok := err.(ValidationErrors)
+				So(ok, ShouldBeTrue)
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0], ShouldResemble, ValidationError{Type: "instance", Name: "app", Field: "start_ip", Message: "Instance IP (10.0.0.10) is already allocated to instance group (web)"})
+			})
+		})
+
+		Convey("When a network has no free ips left", func() {
+			d.Networks[0].Subnet = "10.0.0.0/28"
+			d.Instances[0].StartIP = nil
+			d.Instances[0].Count = 12
+
+			err := d.Validate()
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+
+				errs, ok := err.(ValidationErrors)
+				So(ok, ShouldBeTrue)
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0].Field, ShouldEqual, "count")
+				So(errs[0].Message, ShouldEqual, "Network (web) has no free IP addresses left for instance group")
+			})
+		})
+
+		Convey("When an instance without a start ip is on an ipv6 network", func() {
+			d.VpcSubnet = ""
+			d.VpcID = "vpc-000000"
+			d.Networks[0].Subnet = "fd00::/64"
+			d.Instances[0].StartIP = nil
+
+			err := d.Validate()
+
+			Convey("Then it should reject the network", func() {
+				So(err, ShouldNotBeNil)
+
+				errs, ok := err.(ValidationErrors)
+				So(ok, ShouldBeTrue)
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0], ShouldResemble, ValidationError{Type: "network", Name: "web", Field: "subnet", Message: "Network subnet (fd00::/64) must be an IPv4 range"})
+			})
+		})
+
+		Convey("When a private network spans more zones than its nat gateway's public network", func() {
+			d.Networks = append(d.Networks,
+				Network{Name: "public", Subnet: "10.0.1.0/24", Public: true, AvailabilityZones: []string{"eu-west-1a", "eu-west-1b"}},
+				Network{Name: "private", Subnet: "10.0.2.0/24", NatGateway: "nat", AvailabilityZones: []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"}},
+			)
+			d.NatGateways = []NatGateway{{Name: "nat", PublicNetwork: "public"}}
+
+			err := d.Validate()
+
+			Convey("Then it should reject the zone without a nat gateway", func() {
+				So(err, ShouldNotBeNil)
+
+				errs, ok := err.(ValidationErrors)
+				So(ok, ShouldBeTrue)
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0], ShouldResemble, ValidationError{Type: "network", Name: "private", Field: "availability_zones", Message: "Network availability zone (eu-west-1c) is not used by its nat gateway's public network (public)"})
+			})
+
+			Convey("And the public network is in a single zone", func() {
+				d.Networks[1].AvailabilityZones = nil
+				d.Networks[1].AvailabilityZone = "eu-west-1a"
+
+				Convey("Then every zone should be routed through its nat gateway", func() {
+					So(d.Validate(), ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("When a single field is invalid", func() {
+			d.Name = ""
+
+			err := d.Validate()
+
+			Convey("Then it should return its message", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Service name should not be null")
+			})
+		})
+	})
+}
@@ -4,7 +4,8 @@
 
 package definition
 
-import "errors"
+// EBSMODIFIABLETYPES : Volume types that an existing volume can be modified between
+var EBSMODIFIABLETYPES = []string{"gp2", "io1"}
 
 // EBSVolume ...
 type EBSVolume struct {
@@ -20,34 +21,64 @@ type EBSVolume struct {
 
 // Validate the ebs volume
 func (v *EBSVolume) Validate() error {
+	var errs ValidationErrors
+
 	if v.Name == "" {
-		return errors.New("EBS Volume name should not be null")
+		errs.addField(fieldError("name", "EBS Volume name should not be null"))
 	}
 
 	if v.AvailabilityZone == "" {
-		return errors.New("EBS Volume availability zone name should not be null")
+		errs.addField(fieldError("availability_zone", "EBS Volume availability zone name should not be null"))
 	}
 
 	if v.Type == "" {
-		return errors.New("EBS Volume type should not be null")
+		errs.addField(fieldError("type", "EBS Volume type should not be null"))
 	}
 
 	if v.Encrypted && v.EncryptionKeyID == nil {
-		return errors.New("EBS Volume encryption key id (KMS key id) should be set if volume is encrypted")
+		errs.addField(fieldError("encryption_key_id", "EBS Volume encryption key id (KMS key id) should be set if volume is encrypted"))
 	}
 
 	if v.Type != "io1" && v.Iops != nil {
-		return errors.New("EBS Volume type must be 'io1' when specifying iops")
+		errs.addField(fieldError("iops", "EBS Volume type must be 'io1' when specifying iops"))
 	}
 
 	if v.Size != nil {
 		if *v.Size < 1 || *v.Size > 16384 {
-			return errors.New("EBS Volume size should be between 1 - 16385 (GB)")
+			errs.addField(fieldError("size", "EBS Volume size should be between 1 - 16385 (GB)"))
 		}
 	}
 
 	if v.Count < 1 {
-		return errors.New("EBS volume count should not be less than 1")
+		errs.addField(fieldError("count", "EBS volume count should not be less than 1"))
+	}
+
+	return errs.errorOrNil()
+}
+
+// ValidateChange checks that the volume can be modified from its previous
+// build. Volumes can't shrink, or have their encryption changed. A volume
+// attached to an instance can't be moved to another availability zone, as
+// moving it replaces the volume without detaching it from the instance
+func (v *EBSVolume) ValidateChange(ov *EBSVolume, attached bool) error {
+	if attached && v.AvailabilityZone != ov.AvailabilityZone {
+		return fieldErrorf("availability_zone", "EBS Volume availability zone can't be changed from %s to %s while it is attached to an instance", ov.AvailabilityZone, v.AvailabilityZone)
+	}
+
+	if v.Size != nil && ov.Size != nil && *v.Size < *ov.Size {
+		return fieldErrorf("size", "EBS Volume size can't be decreased from %d to %d (GB)", *ov.Size, *v.Size)
+	}
+
+	if v.Encrypted != ov.Encrypted {
+		return fieldError("encrypted", "EBS Volume encryption can't be changed on an existing volume")
+	}
+
+	if v.EncryptionKeyID != nil && ov.EncryptionKeyID != nil && *v.EncryptionKeyID != *ov.EncryptionKeyID {
+		return fieldError("encryption_key_id", "EBS Volume encryption key id can't be changed on an existing volume")
+	}
+
+	if v.Type != ov.Type && (!isOneOf(EBSMODIFIABLETYPES, v.Type) || !isOneOf(EBSMODIFIABLETYPES, ov.Type)) {
+		return fieldErrorf("type", "EBS Volume type can't be changed from %s to %s", ov.Type, v.Type)
 	}
 
 	return nil
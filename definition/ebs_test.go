@@ -24,6 +24,23 @@ func TestEBSValidate(t *testing.T) {
 			Encrypted:        true,
 			EncryptionKeyID:  pstring("test"),
 		}
+		Convey("With multiple invalid fields", func() {
+			e.Name = ""
+			e.Type = ""
+			e.Count = 0
+			Convey("When validating the ebs volume", func() {
+				err := e.Validate()
+				Convey("Then it should return an error for every invalid field", func() {
+					So(err, ShouldNotBeNil)
+					errs := err.(ValidationErrors)
+					So(len(errs), ShouldEqual, 3)
+					So(errs[0].Field, ShouldEqual, "name")
+					So(errs[1].Field, ShouldEqual, "type")
+					So(errs[2].Field, ShouldEqual, "count")
+				})
+			})
+		})
+
 		Convey("With a valid values", func() {
 			Convey("When validating the ebs", func() {
 				err := e.Validate()
@@ -114,3 +131,84 @@ func TestEBSValidate(t *testing.T) {
 		})
 	})
 }
+
+func TestEBSValidateChange(t *testing.T) {
+	Convey("Given an ebs volume from a previous build", t, func() {
+		ov := EBSVolume{
+			Name:             "foo",
+			Type:             "gp2",
+			Size:             int64p(100),
+			Count:            1,
+			AvailabilityZone: "eu-west-1a",
+		}
+		v := ov
+
+		Convey("When the volume is grown and changed to io1", func() {
+			v.Size = int64p(200)
+			v.Type = "io1"
+			v.Iops = int64p(1000)
+			err := v.ValidateChange(&ov, false)
+			Convey("Then it should not return an error", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When the volume is shrunk", func() {
+			v.Size = int64p(50)
+			err := v.ValidateChange(&ov, false)
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "EBS Volume size can't be decreased from 100 to 50 (GB)")
+			})
+		})
+
+		Convey("When the volume is encrypted", func() {
+			v.Encrypted = true
+			v.EncryptionKeyID = pstring("test")
+			err := v.ValidateChange(&ov, false)
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "EBS Volume encryption can't be changed on an existing volume")
+			})
+		})
+
+		Convey("When the volume is changed to a magnetic volume", func() {
+			v.Type = "standard"
+			err := v.ValidateChange(&ov, false)
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "EBS Volume type can't be changed from gp2 to standard")
+			})
+		})
+
+		Convey("When the volume is moved to another availability zone", func() {
+			v.AvailabilityZone = "eu-west-1b"
+
+			Convey("And it is not attached to an instance", func() {
+				Convey("Then it should not return an error", func() {
+					So(v.ValidateChange(&ov, false), ShouldBeNil)
+				})
+			})
+
+			Convey("And it is attached to an instance", func() {
+				d := Definition{EBSVolumes: []EBSVolume{v}, Instances: []Instance{{Name: "web", Volumes: []InstanceVolume{{Volume: "foo"}}}}}
+				err := d.ValidateChanges(&Definition{EBSVolumes: []EBSVolume{ov}})
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "EBS Volume availability zone can't be changed from eu-west-1a to eu-west-1b while it is attached to an instance")
+				})
+			})
+		})
+
+		Convey("When validating the changes of a definition", func() {
+			v.Size = int64p(50)
+			d := Definition{EBSVolumes: []EBSVolume{v}}
+			err := d.ValidateChanges(&Definition{EBSVolumes: []EBSVolume{ov}})
+			Convey("Then it should return the volume's error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.(ValidationErrors)[0].Type, ShouldEqual, "ebs_volume")
+				So(err.(ValidationErrors)[0].Field, ShouldEqual, "size")
+			})
+		})
+	})
+}
@@ -5,7 +5,6 @@
 package definition
 
 import (
-	"errors"
 	"fmt"
 	"unicode/utf8"
 )
@@ -28,54 +27,53 @@ type ELB struct {
 	Listeners      []ELBListener `json:"listeners"`
 }
 
-// Validate checks if a Network is valid
+// Validate checks if an ELB is valid, returning every field that is not valid
 func (e *ELB) Validate(networks []Network) error {
-	if e.Name == "" {
-		return errors.New("ELB name should not be null")
-	}
+	var errs ValidationErrors
 
-	// Check if network name is > 50 characters
-	if utf8.RuneCountInString(e.Name) > AWSMAXNAME {
-		return fmt.Errorf("ELB name can't be greater than %d characters", AWSMAXNAME)
+	if e.Name == "" {
+		errs.addField(fieldError("name", "ELB name should not be null"))
+	} else if utf8.RuneCountInString(e.Name) > AWSMAXNAME {
+		// Check if elb name is > 50 characters
+		errs.addField(fieldErrorf("name", "ELB name can't be greater than %d characters", AWSMAXNAME))
 	}
 
 	if len(e.Listeners) < 1 {
-		return errors.New("ELB must contain more than one listeners")
+		errs.addField(fieldError("listeners", "ELB must contain more than one listeners"))
 	}
 
 	if e.Private != true && len(e.Subnets) < 1 {
-		return errors.New("ELB must specify at least one subnet if public")
+		errs.addField(fieldError("networks", "ELB must specify at least one subnet if public"))
 	}
 
 	for _, nw := range e.Subnets {
 		for _, n := range networks {
 			if nw == n.Name && n.Public != true && e.Private != true {
-				return fmt.Errorf("ELB subnet (%s) is not a public subnet", nw)
+				errs.addField(fieldErrorf("networks", "ELB subnet (%s) is not a public subnet", nw))
 			}
 		}
 	}
 
-	for _, listener := range e.Listeners {
+	for x, listener := range e.Listeners {
 		if listener.FromPort < 1 || listener.FromPort > 65535 {
-			return fmt.Errorf("From Port (%d) is out of range [1 - 65535]", listener.FromPort)
+			errs.addField(fieldErrorf(fmt.Sprintf("listeners[%d].from_port", x), "From Port (%d) is out of range [1 - 65535]", listener.FromPort))
 		}
 
 		if listener.ToPort < 1 || listener.ToPort > 65535 {
-			return fmt.Errorf("From Port (%d) is out of range [1 - 65535]", listener.ToPort)
+			errs.addField(fieldErrorf(fmt.Sprintf("listeners[%d].to_port", x), "From Port (%d) is out of range [1 - 65535]", listener.ToPort))
 		}
 
 		if listener.Protocol != "http" &&
 			listener.Protocol != "https" &&
 			listener.Protocol != "tcp" &&
 			listener.Protocol != "ssl" {
-			return errors.New("ELB Protocol must be one of http, https, tcp or ssl")
+			errs.addField(fieldError(fmt.Sprintf("listeners[%d].protocol", x), "ELB Protocol must be one of http, https, tcp or ssl"))
 		}
 
 		if listener.Protocol == "https" && listener.SSLCert == "" || listener.Protocol == "ssl" && listener.SSLCert == "" {
-			return errors.New("ELB listener must specify an ssl cert when protocol is https/ssl")
+			errs.addField(fieldError(fmt.Sprintf("listeners[%d].ssl_cert", x), "ELB listener must specify an ssl cert when protocol is https/ssl"))
 		}
-
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
@@ -0,0 +1,103 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError : A validation failure on a single field of a component
+type ValidationError struct {
+	Type    string `json:"type,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error returns the validation error message
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors : Every validation failure found on a definition
+type ValidationErrors []ValidationError
+
+// Error returns all validation error messages
+func (e ValidationErrors) Error() string {
+	var messages []string
+	for _, err := range e {
+		messages = append(messages, err.Message)
+	}
+	return strings.Join(messages, ", ")
+}
+
+// add records the validation errors of a component, if there are any
+func (e *ValidationErrors) add(ctype, name string, err error) {
+	for _, verr := range validationErrors(err) {
+		verr.Type = ctype
+		verr.Name = name
+
+		*e = append(*e, verr)
+	}
+}
+
+// addField records a field validation error while validating a component,
+// if there is one
+func (e *ValidationErrors) addField(err error) {
+	*e = append(*e, validationErrors(err)...)
+}
+
+// errorOrNil returns the validation errors as an error, or nil if there
+// are none
+func (e ValidationErrors) errorOrNil() error {
+	if len(e) < 1 {
+		return nil
+	}
+	return e
+}
+
+// validationErrors splits an error into the validation errors it holds
+func validationErrors(err error) ValidationErrors {
+	switch verr := err.(type) {
+	case nil:
+		return nil
+	case ValidationErrors:
+		return verr
+	case ValidationError:
+		return ValidationErrors{verr}
+	}
+
+	return ValidationErrors{ValidationError{Message: err.Error()}}
+}
+
+func fieldError(field, message string) error {
+	return ValidationError{Field: field, Message: message}
+}
+
+func fieldErrorf(field, format string, a ...interface{}) error {
+	return ValidationError{Field: field, Message: fmt.Sprintf(format, a...)}
+}
+
+// withField prefixes the field of an error with the path of its parent
+// field. Errors holding multiple validation errors have every field prefixed
+func withField(path string, err error) error {
+	if errs, ok := err.(ValidationErrors); ok {
+		var prefixed ValidationErrors
+		for _, verr := range errs {
+			prefixed = append(prefixed, withField(path, verr).(ValidationError))
+		}
+		return prefixed.errorOrNil()
+	}
+
+	verr, ok := err.(ValidationError)
+	if !ok || verr.Field == "" {
+		return fieldError(path, err.Error())
+	}
+
+	verr.Field = path + "." + verr.Field
+
+	return verr
+}
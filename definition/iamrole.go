@@ -0,0 +1,72 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// IAMPolicy ...
+type IAMPolicy struct {
+	Name     string `json:"name"`
+	Document string `json:"document"`
+}
+
+// IAMRole ...
+type IAMRole struct {
+	Name             string      `json:"name"`
+	AssumeRolePolicy string      `json:"assume_role_policy,omitempty"`
+	Policies         []IAMPolicy `json:"policies,omitempty"`
+	ManagedPolicies  []string    `json:"managed_policies,omitempty"`
+}
+
+// Validate checks if an iam role is valid
+func (r *IAMRole) Validate() error {
+	var errs ValidationErrors
+
+	if r.Name == "" {
+		errs.addField(fieldError("name", "IAM role name should not be null"))
+	} else if utf8.RuneCountInString(r.Name) > AWSMAXNAME {
+		errs.addField(fieldErrorf("name", "IAM role name can't be greater than %d characters", AWSMAXNAME))
+	}
+
+	if r.AssumeRolePolicy != "" && isJSON(r.AssumeRolePolicy) != true {
+		errs.addField(fieldError("assume_role_policy", "IAM role assume role policy is not a valid json document"))
+	}
+
+	for x, p := range r.Policies {
+		if p.Name == "" {
+			errs.addField(fieldError(fmt.Sprintf("policies[%d].name", x), "IAM policy name should not be null"))
+		}
+
+		if p.Document == "" {
+			errs.addField(fieldError(fmt.Sprintf("policies[%d].document", x), "IAM policy document should not be null"))
+		} else if isJSON(p.Document) != true {
+			errs.addField(fieldErrorf(fmt.Sprintf("policies[%d].document", x), "IAM policy (%s) document is not a valid json document", p.Name))
+		}
+
+		for _, op := range r.Policies[:x] {
+			if op.Name == p.Name {
+				errs.addField(fieldErrorf(fmt.Sprintf("policies[%d].name", x), "IAM policy (%s) is defined more than once", p.Name))
+			}
+		}
+	}
+
+	for x, arn := range r.ManagedPolicies {
+		if strings.HasPrefix(arn, "arn:aws:iam::") != true {
+			errs.addField(fieldErrorf(fmt.Sprintf("managed_policies[%d]", x), "IAM managed policy (%s) is not a valid policy arn", arn))
+		}
+	}
+
+	return errs.errorOrNil()
+}
+
+func isJSON(document string) bool {
+	var v map[string]interface{}
+	return json.Unmarshal([]byte(document), &v) == nil
+}
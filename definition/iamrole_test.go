@@ -0,0 +1,92 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIAMRoleValidate(t *testing.T) {
+	Convey("Given an iam role", t, func() {
+		r := IAMRole{
+			Name: "web",
+			Policies: []IAMPolicy{
+				IAMPolicy{Name: "s3", Document: `{"Version":"2012-10-17","Statement":[]}`},
+			},
+			ManagedPolicies: []string{"arn:aws:iam::aws:policy/ReadOnlyAccess"},
+		}
+
+		Convey("With multiple invalid fields", func() {
+			r.Name = ""
+			r.AssumeRolePolicy = "invalid"
+			Convey("When validating the iam role", func() {
+				err := r.Validate()
+				Convey("Then it should return an error for every invalid field", func() {
+					So(err, ShouldNotBeNil)
+					errs := err.(ValidationErrors)
+					So(len(errs), ShouldEqual, 2)
+					So(errs[0].Field, ShouldEqual, "name")
+					So(errs[1].Field, ShouldEqual, "assume_role_policy")
+				})
+			})
+		})
+
+		Convey("With a valid configuration", func() {
+			Convey("When validating the iam role", func() {
+				err := r.Validate()
+				Convey("Then it should not return an error", func() {
+					So(err, ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("With an invalid policy document", func() {
+			r.Policies[0].Document = "{"
+			Convey("When validating the iam role", func() {
+				err := r.Validate()
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Field, ShouldEqual, "policies[0].document")
+					So(err.Error(), ShouldEqual, "IAM policy (s3) document is not a valid json document")
+				})
+			})
+		})
+
+		Convey("With duplicate policies", func() {
+			r.Policies = append(r.Policies, r.Policies[0])
+			Convey("When validating the iam role", func() {
+				err := r.Validate()
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "IAM policy (s3) is defined more than once")
+				})
+			})
+		})
+
+		Convey("With an invalid managed policy", func() {
+			r.ManagedPolicies = []string{"ReadOnlyAccess"}
+			Convey("When validating the iam role", func() {
+				err := r.Validate()
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Field, ShouldEqual, "managed_policies[0]")
+				})
+			})
+		})
+
+		Convey("With an invalid assume role policy", func() {
+			r.AssumeRolePolicy = "ec2"
+			Convey("When validating the iam role", func() {
+				err := r.Validate()
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Field, ShouldEqual, "assume_role_policy")
+				})
+			})
+		})
+	})
+}
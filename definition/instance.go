@@ -5,8 +5,6 @@
 package definition
 
 import (
-	"errors"
-	"fmt"
 	"net"
 	"unicode/utf8"
 )
@@ -30,66 +28,88 @@ type Instance struct {
 	SecurityGroups []string         `json:"security_groups"`
 	Volumes        []InstanceVolume `json:"volumes"`
 	UserData       string           `json:"user_data"`
+	IAMProfile     string           `json:"iam_profile,omitempty"`
 }
 
-// Validate : Validates the instance returning true or false if is valid or not
+// Validate : Validates the instance, returning every field that is not valid
 func (i *Instance) Validate(network *Network, volumes []EBSVolume) error {
-	if i.Name == "" {
-		return errors.New("Instance name should not be null")
-	}
+	var errs ValidationErrors
 
-	if utf8.RuneCountInString(i.Name) > AWSMAXNAME {
-		return fmt.Errorf("Instance name can't be greater than %d characters", AWSMAXNAME)
+	if i.Name == "" {
+		errs.addField(fieldError("name", "Instance name should not be null"))
+	} else if utf8.RuneCountInString(i.Name) > AWSMAXNAME {
+		errs.addField(fieldErrorf("name", "Instance name can't be greater than %d characters", AWSMAXNAME))
 	}
 
 	if i.Type == "" {
-		return errors.New("Instance type should not be null")
+		errs.addField(fieldError("type", "Instance type should not be null"))
 	}
 
 	if i.Image == "" {
-		return errors.New("Instance image should not be null")
+		errs.addField(fieldError("image", "Instance image should not be null"))
 	}
 
 	if i.Count < 1 {
-		return errors.New("Instance count should not be < 1")
+		errs.addField(fieldError("count", "Instance count should not be < 1"))
 	}
 
 	if i.Network == "" {
-		return errors.New("Instance network should not be null")
+		errs.addField(fieldError("network", "Instance network should not be null"))
 	}
 
-	// Validate IP addresses
-	if network != nil {
-		_, nw, err := net.ParseCIDR(network.Subnet)
-		if err != nil {
-			return errors.New("Could not process network")
-		}
-
-		startIP := net.ParseIP(i.StartIP.String()).To4()
-		ip := make(net.IP, net.IPv4len)
-		copy(ip, i.StartIP.To4())
-
-		for x := 0; x < i.Count; x++ {
-			if !nw.Contains(ip) {
-				return errors.New("Instance IP invalid. IP must be a valid IP in the same range as it's network")
-			}
-
-			// Check IP is greater than Start IP (Bounds checking)
-			if ip[3] < startIP[3] {
-				return errors.New("Instance IP invalid. Allocated IP is lower than Start IP")
-			}
+	if err := validateSecret(i.UserData); err != nil {
+		errs.addField(fieldErrorf("user_data", "Instance user data secret reference is not valid: %s", err.Error()))
+	}
 
-			ip[3]++
-		}
+	if network != nil && i.StartIP != nil {
+		errs.addField(i.validateStartIP(network))
 	}
 
 	for _, vol := range i.Volumes {
 		for _, v := range volumes {
 			if v.Name == vol.Volume && v.Count < i.Count {
-				return errors.New("Instance count is higher than the specified ebs volume count attached to this instance")
+				errs.addField(fieldError("volumes", "Instance count is higher than the specified ebs volume count attached to this instance"))
 			}
 		}
 	}
 
+	return errs.errorOrNil()
+}
+
+// validateStartIP checks every ip allocated from the start ip is a valid
+// address on the instance network
+func (i *Instance) validateStartIP(network *Network) error {
+	if len(network.AvailabilityZones) > 0 {
+		return fieldError("start_ip", "Instance IP invalid. Start IP can't be used with a network spanning multiple availability zones")
+	}
+
+	_, nw, err := net.ParseCIDR(network.Subnet)
+	if err != nil {
+		return fieldError("network", "Could not process network")
+	}
+
+	if i.StartIP.To4() == nil {
+		return fieldError("start_ip", "Instance IP invalid. Start IP must be a valid IPv4 address")
+	}
+
+	start := ipToInt(i.StartIP)
+
+	for x := 0; x < i.Count; x++ {
+		// Check the allocated ip has not overflowed the address space
+		if start+uint32(x) < start {
+			return fieldError("start_ip", "Instance IP invalid. IP must be a valid IP in the same range as it's network")
+		}
+
+		ip := intToIP(start + uint32(x))
+
+		if !nw.Contains(ip) {
+			return fieldError("start_ip", "Instance IP invalid. IP must be a valid IP in the same range as it's network")
+		}
+
+		if network.IsReservedIP(ip) {
+			return fieldErrorf("start_ip", "Instance IP invalid. IP (%s) is reserved by AWS", ip)
+		}
+	}
+
 	return nil
 }
@@ -61,6 +61,23 @@ func TestInstanceValidate(t *testing.T) {
 			})
 		})
 
+		Convey("With multiple invalid fields", func() {
+			i.Type = ""
+			i.Image = ""
+			i.Count = 0
+			Convey("When validating the instance", func() {
+				err := i.Validate(n, v)
+				Convey("Then should return an error for every invalid field", func() {
+					So(err, ShouldNotBeNil)
+					errs := err.(ValidationErrors)
+					So(len(errs), ShouldEqual, 3)
+					So(errs[0].Field, ShouldEqual, "type")
+					So(errs[1].Field, ShouldEqual, "image")
+					So(errs[2].Field, ShouldEqual, "count")
+				})
+			})
+		})
+
 		Convey("With an instance count less than one", func() {
 			i.Count = 0
 			Convey("When validating the instance", func() {
@@ -83,6 +100,17 @@ func TestInstanceValidate(t *testing.T) {
 			})
 		})
 
+		Convey("With a start IP on a network across multiple availability zones", func() {
+			n.AvailabilityZones = []string{"eu-west-1a", "eu-west-1b"}
+			Convey("When validating the instance", func() {
+				err := i.Validate(n, v)
+				Convey("Then should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "Instance IP invalid. Start IP can't be used with a network spanning multiple availability zones")
+				})
+			})
+		})
+
 		Convey("With an start IP that is outside of the networks range", func() {
 			i.StartIP = net.ParseIP("10.10.10.10")
 			Convey("When validating the instance", func() {
@@ -94,6 +122,53 @@ func TestInstanceValidate(t *testing.T) {
 			})
 		})
 
+		Convey("With ips that cross an octet boundary", func() {
+			n.Subnet = "127.0.0.0/16"
+			i.StartIP = net.ParseIP("127.0.0.255")
+			i.Volumes = nil
+			i.Count = 2
+			Convey("When validating the instance", func() {
+				err := i.Validate(n, v)
+				Convey("Then should not return an error", func() {
+					So(err, ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("With a start ip reserved by aws", func() {
+			i.StartIP = net.ParseIP("127.0.0.2")
+			Convey("When validating the instance", func() {
+				err := i.Validate(n, v)
+				Convey("Then should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "Instance IP invalid. IP (127.0.0.2) is reserved by AWS")
+				})
+			})
+		})
+
+		Convey("With an ip allocated to the broadcast address", func() {
+			i.StartIP = net.ParseIP("127.0.0.254")
+			i.Volumes = nil
+			i.Count = 2
+			Convey("When validating the instance", func() {
+				err := i.Validate(n, v)
+				Convey("Then should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "Instance IP invalid. IP (127.0.0.255) is reserved by AWS")
+				})
+			})
+		})
+
+		Convey("Without a start ip", func() {
+			i.StartIP = nil
+			Convey("When validating the instance", func() {
+				err := i.Validate(n, v)
+				Convey("Then should not return an error", func() {
+					So(err, ShouldBeNil)
+				})
+			})
+		})
+
 		Convey("With an ebs volume specified that doesn't have a high enough count", func() {
 			i.Count = 5
 			Convey("When validating the instance", func() {
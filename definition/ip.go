@@ -0,0 +1,181 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+)
+
+// AWSRESERVEDIPS : Number of addresses aws reserves at the start of every subnet
+const AWSRESERVEDIPS = 4
+
+// ipToInt converts an ipv4 address to its integer representation. Any
+// address that is not ipv4 is converted to 0
+func ipToInt(ip net.IP) uint32 {
+	v4 := ip.To4()
+	if v4 == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(v4)
+}
+
+// intToIP converts an integer to its ipv4 address representation
+func intToIP(n uint32) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, n)
+	return ip
+}
+
+// usableIPRange returns the first and last address of a subnet that can be
+// assigned to an instance. AWS reserves the first four addresses and the
+// broadcast address of every subnet. A subnet that is not ipv4 has no
+// usable addresses
+func usableIPRange(nw *net.IPNet) (uint32, uint32) {
+	if nw.IP.To4() == nil {
+		return 1, 0
+	}
+
+	ones, bits := nw.Mask.Size()
+	size := uint64(1) << uint(bits-ones)
+	first := ipToInt(nw.IP)
+
+	if size <= AWSRESERVEDIPS+1 {
+		return first + 1, first
+	}
+
+	return first + AWSRESERVEDIPS, uint32(uint64(first) + size - 2)
+}
+
+// IsReservedIP returns true if an ip address of the network can't be
+// assigned to an instance
+func (n *Network) IsReservedIP(ip net.IP) bool {
+	_, nw, err := net.ParseCIDR(n.Subnet)
+	if err != nil || ip.To4() == nil || !nw.Contains(ip) {
+		return false
+	}
+
+	first, last := usableIPRange(nw)
+	x := ipToInt(ip)
+
+	return x < first || x > last
+}
+
+// InstanceIPs allocates an ip address to every instance, returning them by
+// instance group. Groups with a start ip are given consecutive addresses
+// from it. Instances of groups without one keep the address they were
+// previously given, or are given the lowest free address of their network,
+// in the order they are defined. Instances on a network with multiple
+// availability zones are spread across its subnets in turn
+func (d *Definition) InstanceIPs() (map[string][]net.IP, ValidationErrors) {
+	var errs ValidationErrors
+
+	ips := make(map[string][]net.IP)
+	allocated := make(map[string]map[uint32]string)
+
+	for _, nw := range d.Networks {
+		for _, s := range nw.Subnets() {
+			allocated[s.Name] = make(map[uint32]string)
+		}
+	}
+
+	for _, i := range d.Instances {
+		nw := d.FindNetwork(i.Network)
+		if i.StartIP == nil || nw == nil || len(nw.AvailabilityZones) > 0 || i.StartIP.To4() == nil {
+			continue
+		}
+
+		start := ipToInt(i.StartIP)
+		for x := 0; x < i.Count; x++ {
+			ip := start + uint32(x)
+
+			if owner, ok := allocated[i.Network][ip]; ok {
+				errs.add("instance", i.Name, fieldErrorf("start_ip", "Instance IP (%s) is already allocated to instance group (%s)", intToIP(ip), owner))
+				break
+			}
+
+			allocated[i.Network][ip] = i.Name
+			ips[i.Name] = append(ips[i.Name], intToIP(ip))
+		}
+	}
+
+	// keep the addresses of existing instances, so that adding instances
+	// to a group never moves the instances of another
+	for _, i := range d.Instances {
+		nw := d.FindNetwork(i.Network)
+		if i.StartIP != nil || nw == nil {
+			continue
+		}
+
+		subnets := nw.Subnets()
+		ips[i.Name] = make([]net.IP, i.Count)
+
+		for x := 0; x < i.Count; x++ {
+			prev := d.PreviousIPs[i.Name+"-"+strconv.Itoa(x+1)]
+			if prev.To4() == nil {
+				continue
+			}
+
+			s := subnets[x%len(subnets)]
+			_, snw, err := net.ParseCIDR(s.Subnet)
+			if err != nil || !snw.Contains(prev) || s.IsReservedIP(prev) || isAllocated(allocated[s.Name], ipToInt(prev)) {
+				continue
+			}
+
+			allocated[s.Name][ipToInt(prev)] = i.Name
+			ips[i.Name][x] = prev.To4()
+		}
+	}
+
+	for _, i := range d.Instances {
+		nw := d.FindNetwork(i.Network)
+		if i.StartIP != nil || nw == nil {
+			continue
+		}
+
+		subnets := nw.Subnets()
+		next := make([]uint64, len(subnets))
+
+		for x := 0; x < i.Count; x++ {
+			if ips[i.Name][x] != nil {
+				continue
+			}
+
+			s := subnets[x%len(subnets)]
+
+			_, snw, err := net.ParseCIDR(s.Subnet)
+			if err != nil || snw.IP.To4() == nil {
+				break
+			}
+
+			first, last := usableIPRange(snw)
+			ip := next[x%len(subnets)]
+			if ip < uint64(first) {
+				ip = uint64(first)
+			}
+
+			for ip <= uint64(last) && isAllocated(allocated[s.Name], uint32(ip)) {
+				ip++
+			}
+
+			if ip > uint64(last) {
+				errs.add("instance", i.Name, fieldErrorf("count", "Network (%s) has no free IP addresses left for instance group", s.Name))
+				break
+			}
+
+			allocated[s.Name][uint32(ip)] = i.Name
+			ips[i.Name][x] = intToIP(uint32(ip))
+			next[x%len(subnets)] = ip + 1
+		}
+	}
+
+	return ips, errs
+}
+
+func isAllocated(allocated map[uint32]string, ip uint32) bool {
+	_, ok := allocated[ip]
+	return ok
+}
@@ -5,8 +5,6 @@
 package definition
 
 import (
-	"errors"
-	"fmt"
 	"unicode/utf8"
 )
 
@@ -16,26 +14,30 @@ type NatGateway struct {
 	PublicNetwork string `json:"public_network"`
 }
 
-// Validate checks if a Network is valid
+// Validate checks if a Nat Gateway is valid, returning every field that is not valid
 func (n *NatGateway) Validate(networks []Network) error {
-	if n.Name == "" {
-		return errors.New("Nat Gateway name should not be null")
-	}
+	var errs ValidationErrors
 
-	// Check if network name is > 50 characters
-	if utf8.RuneCountInString(n.Name) > AWSMAXNAME {
-		return fmt.Errorf("Nat Gateway name can't be greater than %d characters", AWSMAXNAME)
+	if n.Name == "" {
+		errs.addField(fieldError("name", "Nat Gateway name should not be null"))
+	} else if utf8.RuneCountInString(n.Name) > AWSMAXNAME {
+		errs.addField(fieldErrorf("name", "Nat Gateway name can't be greater than %d characters", AWSMAXNAME))
 	}
 
 	if n.PublicNetwork == "" {
-		return errors.New("Nat Gateway should specify a public network")
+		errs.addField(fieldError("public_network", "Nat Gateway should specify a public network"))
+	} else if !n.hasPublicNetwork(networks) {
+		errs.addField(fieldError("public_network", "Nat Gateway public network is not defined"))
 	}
 
+	return errs.errorOrNil()
+}
+
+func (n *NatGateway) hasPublicNetwork(networks []Network) bool {
 	for _, nw := range networks {
 		if nw.Name == n.PublicNetwork && nw.Public {
-			return nil
+			return true
 		}
 	}
-
-	return errors.New("Nat Gateway public network is not defined")
+	return false
 }
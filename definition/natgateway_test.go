@@ -14,6 +14,21 @@ func TestNatGatewayValidate(t *testing.T) {
 	Convey("Given a nat gateway", t, func() {
 		networks := []Network{Network{Name: "test", Public: true}}
 		n := NatGateway{Name: "foo", PublicNetwork: "test"}
+		Convey("With multiple invalid fields", func() {
+			n.Name = ""
+			n.PublicNetwork = ""
+			Convey("When validating the nat gateway", func() {
+				err := n.Validate(networks)
+				Convey("Then it should return an error for every invalid field", func() {
+					So(err, ShouldNotBeNil)
+					errs := err.(ValidationErrors)
+					So(len(errs), ShouldEqual, 2)
+					So(errs[0].Field, ShouldEqual, "name")
+					So(errs[1].Field, ShouldEqual, "public_network")
+				})
+			})
+		})
+
 		Convey("With a valid subnet", func() {
 			Convey("When validating the nat gateway", func() {
 				err := n.Validate(networks)
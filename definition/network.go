@@ -5,8 +5,6 @@
 package definition
 
 import (
-	"errors"
-	"fmt"
 	"net"
 	"strings"
 	"unicode/utf8"
@@ -14,38 +12,190 @@ import (
 
 // Network ...
 type Network struct {
-	Name             string `json:"name"`
-	Subnet           string `json:"subnet"`
-	Public           bool   `json:"public"`
-	NatGateway       string `json:"nat_gateway"`
-	AvailabilityZone string `json:"availability_zone"`
+	Name              string   `json:"name"`
+	Subnet            string   `json:"subnet,omitempty"`
+	Size              int      `json:"size,omitempty"`
+	Public            bool     `json:"public"`
+	NatGateway        string   `json:"nat_gateway"`
+	AvailabilityZone  string   `json:"availability_zone"`
+	AvailabilityZones []string `json:"availability_zones,omitempty"`
 }
 
-// Validate checks if a Network is valid
+// Validate checks if a Network is valid, returning every field that is not valid
 func (n *Network) Validate(datacenter *Datacenter) error {
-	_, _, err := net.ParseCIDR(n.Subnet)
-	if err != nil {
-		return errors.New("Network CIDR is not valid")
+	var errs ValidationErrors
+
+	if n.Size != 0 && (n.Size < MINSUBNETSIZE || n.Size > MAXSUBNETSIZE) {
+		errs.addField(fieldErrorf("size", "Network size must be between %d and %d", MINSUBNETSIZE, MAXSUBNETSIZE))
 	}
 
-	if n.Name == "" {
-		return errors.New("Network name should not be null")
+	// Networks only specifying a size are allocated a subnet by the mapper
+	if n.Size == 0 || n.Subnet != "" {
+		_, nw, err := net.ParseCIDR(n.Subnet)
+		if err != nil {
+			errs.addField(fieldError("subnet", "Network CIDR is not valid"))
+		} else if nw.IP.To4() == nil {
+			errs.addField(fieldErrorf("subnet", "Network subnet (%s) must be an IPv4 range", n.Subnet))
+		} else if ones, _ := nw.Mask.Size(); n.Size != 0 && ones != n.Size {
+			errs.addField(fieldErrorf("subnet", "Network subnet (%s) does not match its size (/%d)", n.Subnet, n.Size))
+		}
 	}
 
-	// Check if network name is > 50 characters
-	if utf8.RuneCountInString(n.Name) > AWSMAXNAME {
-		return fmt.Errorf("Network name can't be greater than %d characters", AWSMAXNAME)
+	if n.Name == "" {
+		errs.addField(fieldError("name", "Network name should not be null"))
+	} else if utf8.RuneCountInString(n.Name) > AWSMAXNAME {
+		// Check if network name is > 50 characters
+		errs.addField(fieldErrorf("name", "Network name can't be greater than %d characters", AWSMAXNAME))
 	}
 
 	if n.Public && n.NatGateway != "" {
-		return errors.New("Public Network should not specify a nat gateway")
+		errs.addField(fieldError("nat_gateway", "Public Network should not specify a nat gateway"))
 	}
 
 	if n.AvailabilityZone != "" {
 		if !strings.Contains(n.AvailabilityZone, datacenter.Region) {
-			return errors.New("Network availability zone must be in the same region as the vpc")
+			errs.addField(fieldError("availability_zone", "Network availability zone must be in the same region as the vpc"))
+		}
+	}
+
+	if len(n.AvailabilityZones) > 0 {
+		if n.AvailabilityZone != "" {
+			errs.addField(fieldError("availability_zones", "Network should not specify both availability_zone and availability_zones"))
+		}
+
+		var azs []string
+		for _, az := range n.AvailabilityZones {
+			if !strings.Contains(az, datacenter.Region) {
+				errs.addField(fieldErrorf("availability_zones", "Network availability zone (%s) must be in the same region as the vpc", az))
+			}
+			if isOneOf(azs, az) {
+				errs.addField(fieldErrorf("availability_zones", "Network availability zone (%s) is specified more than once", az))
+			}
+			azs = append(azs, az)
+		}
+
+		if _, nw, err := net.ParseCIDR(n.Subnet); err == nil && nw.IP.To4() != nil {
+			ones, _ := nw.Mask.Size()
+			if ones+zoneBits(len(n.AvailabilityZones)) > MAXSUBNETSIZE {
+				errs.addField(fieldErrorf("subnet", "Network subnet (%s) is too small to be split across %d availability zones", n.Subnet, len(n.AvailabilityZones)))
+			}
 		}
 	}
 
+	return errs.errorOrNil()
+}
+
+// ValidateChange checks that the network can be changed from its previous
+// build. Changing the range or availability zone of a network replaces it,
+// which can't be done while other components are placed on it. Changing the
+// availability zones a network spans resizes its subnets and moves the
+// instances spread across them, so it is rejected for the same reason
+func (n *Network) ValidateChange(on *Network, used bool) error {
+	if !used {
+		return nil
+	}
+
+	if len(n.AvailabilityZones) > 0 || len(on.AvailabilityZones) > 0 {
+		if strings.Join(n.AvailabilityZones, ",") != strings.Join(on.AvailabilityZones, ",") {
+			return fieldErrorf("availability_zones", "Network availability zones can't be changed from [%s] to [%s] while other components are placed on it", strings.Join(on.AvailabilityZones, ", "), strings.Join(n.AvailabilityZones, ", "))
+		}
+	}
+
+	if n.Subnet != "" && on.Subnet != "" && n.Subnet != on.Subnet {
+		return fieldErrorf("subnet", "Network range can't be changed from %s to %s while other components are placed on it", on.Subnet, n.Subnet)
+	}
+
+	if n.AvailabilityZone != "" && on.AvailabilityZone != "" && n.AvailabilityZone != on.AvailabilityZone {
+		return fieldErrorf("availability_zone", "Network availability zone can't be changed from %s to %s while other components are placed on it", on.AvailabilityZone, n.AvailabilityZone)
+	}
+
 	return nil
 }
+
+// Zones returns all availability zones a network spans
+func (n *Network) Zones() []string {
+	if len(n.AvailabilityZones) > 0 {
+		return n.AvailabilityZones
+	}
+	return []string{n.AvailabilityZone}
+}
+
+// Subnets returns the subnets a network is expanded into. A network with
+// multiple availability zones has its subnet split into equal blocks, one
+// for each zone, named after the last letter of the zone. Any other network
+// is returned unchanged
+func (n *Network) Subnets() []Network {
+	if len(n.AvailabilityZones) < 1 {
+		return []Network{*n}
+	}
+
+	var subnets []Network
+
+	bits := zoneBits(len(n.AvailabilityZones))
+	_, nw, err := net.ParseCIDR(n.Subnet)
+
+	for x, az := range n.AvailabilityZones {
+		s := Network{
+			Name:             n.Name + ZoneSuffix(az),
+			Public:           n.Public,
+			NatGateway:       n.NatGateway,
+			AvailabilityZone: az,
+		}
+
+		// only ipv4 subnets can be split, anything else fails validation
+		if err == nil && nw.IP.To4() != nil {
+			ones, size := nw.Mask.Size()
+			block := uint32(1) << uint(size-ones-bits)
+			ip := intToIP(ipToInt(nw.IP) + uint32(x)*block)
+			s.Subnet = (&net.IPNet{IP: ip, Mask: net.CIDRMask(ones+bits, size)}).String()
+		}
+
+		subnets = append(subnets, s)
+	}
+
+	return subnets
+}
+
+// ZoneSuffix returns the suffix given to the subnet of a network in an
+// availability zone
+func ZoneSuffix(az string) string {
+	if az == "" {
+		return ""
+	}
+	return "-" + az[len(az)-1:]
+}
+
+// JoinSubnets returns the network subnet that was split into the given
+// availability zone subnets
+func JoinSubnets(subnets []string) string {
+	var first *net.IPNet
+
+	for _, s := range subnets {
+		_, nw, err := net.ParseCIDR(s)
+		if err != nil {
+			return ""
+		}
+		if first == nil || ipToInt(nw.IP) < ipToInt(first.IP) {
+			first = nw
+		}
+	}
+
+	if first == nil {
+		return ""
+	}
+
+	ones, size := first.Mask.Size()
+	mask := net.CIDRMask(ones-zoneBits(len(subnets)), size)
+
+	return (&net.IPNet{IP: first.IP.Mask(mask), Mask: mask}).String()
+}
+
+// zoneBits returns the number of prefix bits needed to split a subnet
+// across a number of availability zones
+func zoneBits(zones int) int {
+	bits := 0
+	for (1 << uint(bits)) < zones {
+		bits++
+	}
+	return bits
+}
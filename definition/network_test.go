@@ -23,6 +23,51 @@ func TestNetworkValidate(t *testing.T) {
 			})
 		})
 
+		Convey("With a size and no subnet", func() {
+			n.Subnet = ""
+			n.Size = 24
+			Convey("When validating the network", func() {
+				err := n.Validate(&d)
+				Convey("Then it should not return an error", func() {
+					So(err, ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("With a size that is out of range", func() {
+			n.Subnet = ""
+			n.Size = 30
+			Convey("When validating the network", func() {
+				err := n.Validate(&d)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "Network size must be between 16 and 28")
+				})
+			})
+		})
+
+		Convey("With a subnet that does not match its size", func() {
+			n.Size = 24
+			Convey("When validating the network", func() {
+				err := n.Validate(&d)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Field, ShouldEqual, "subnet")
+				})
+			})
+		})
+
+		Convey("With an ipv6 subnet", func() {
+			n.Subnet = "fd00::/64"
+			Convey("When validating the network", func() {
+				err := n.Validate(&d)
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "Network subnet (fd00::/64) must be an IPv4 range")
+				})
+			})
+		})
+
 		Convey("With an invalid subnet", func() {
 			n.Subnet = "10.11.1.11"
 			Convey("When validating the network", func() {
@@ -63,6 +108,62 @@ func TestNetworkValidate(t *testing.T) {
 			})
 		})
 
+		Convey("With multiple availability zones", func() {
+			n.Subnet = "10.1.0.0/24"
+			n.AvailabilityZone = ""
+			n.AvailabilityZones = []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"}
+			Convey("When validating the network", func() {
+				err := n.Validate(&d)
+				Convey("Then it should not return an error", func() {
+					So(err, ShouldBeNil)
+				})
+			})
+
+			Convey("And an availability zone", func() {
+				n.AvailabilityZone = "eu-west-1a"
+				Convey("When validating the network", func() {
+					err := n.Validate(&d)
+					Convey("Then it should return an error", func() {
+						So(err, ShouldNotBeNil)
+						So(err.Error(), ShouldEqual, "Network should not specify both availability_zone and availability_zones")
+					})
+				})
+			})
+
+			Convey("And an availability zone outside of the datacenter region", func() {
+				n.AvailabilityZones[1] = "us-east-1b"
+				Convey("When validating the network", func() {
+					err := n.Validate(&d)
+					Convey("Then it should return an error", func() {
+						So(err, ShouldNotBeNil)
+						So(err.Error(), ShouldEqual, "Network availability zone (us-east-1b) must be in the same region as the vpc")
+					})
+				})
+			})
+
+			Convey("And a duplicate availability zone", func() {
+				n.AvailabilityZones[2] = "eu-west-1a"
+				Convey("When validating the network", func() {
+					err := n.Validate(&d)
+					Convey("Then it should return an error", func() {
+						So(err, ShouldNotBeNil)
+						So(err.Error(), ShouldEqual, "Network availability zone (eu-west-1a) is specified more than once")
+					})
+				})
+			})
+
+			Convey("And a subnet too small to be split", func() {
+				n.Subnet = "10.1.0.0/27"
+				Convey("When validating the network", func() {
+					err := n.Validate(&d)
+					Convey("Then it should return an error", func() {
+						So(err, ShouldNotBeNil)
+						So(err.Error(), ShouldEqual, "Network subnet (10.1.0.0/27) is too small to be split across 3 availability zones")
+					})
+				})
+			})
+		})
+
 		Convey("With an availability zone that does not correspond to the datacenter region", func() {
 			n.AvailabilityZone = "us-east-1a"
 			Convey("When validating the network", func() {
@@ -75,3 +176,118 @@ func TestNetworkValidate(t *testing.T) {
 
 	})
 }
+
+func TestNetworkSubnets(t *testing.T) {
+	Convey("Given a network with multiple availability zones", t, func() {
+		n := Network{
+			Name:              "web",
+			Subnet:            "10.1.0.0/24",
+			Public:            true,
+			AvailabilityZones: []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"},
+		}
+
+		Convey("When getting its subnets", func() {
+			s := n.Subnets()
+			Convey("Then it should split the subnet across every availability zone", func() {
+				So(len(s), ShouldEqual, 3)
+				So(s[0].Name, ShouldEqual, "web-a")
+				So(s[0].Subnet, ShouldEqual, "10.1.0.0/26")
+				So(s[0].AvailabilityZone, ShouldEqual, "eu-west-1a")
+				So(s[0].Public, ShouldBeTrue)
+				So(s[1].Name, ShouldEqual, "web-b")
+				So(s[1].Subnet, ShouldEqual, "10.1.0.64/26")
+				So(s[2].Name, ShouldEqual, "web-c")
+				So(s[2].Subnet, ShouldEqual, "10.1.0.128/26")
+				So(s[2].AvailabilityZone, ShouldEqual, "eu-west-1c")
+			})
+
+			Convey("And joining them should return the network subnet", func() {
+				So(JoinSubnets([]string{s[0].Subnet, s[1].Subnet, s[2].Subnet}), ShouldEqual, "10.1.0.0/24")
+			})
+		})
+	})
+
+	Convey("Given an ipv6 network with multiple availability zones", t, func() {
+		n := Network{Name: "web", Subnet: "fd00::/64", AvailabilityZones: []string{"eu-west-1a", "eu-west-1b"}}
+
+		Convey("When getting its subnets", func() {
+			s := n.Subnets()
+			Convey("Then it should not split the subnet", func() {
+				So(len(s), ShouldEqual, 2)
+				So(s[0].Name, ShouldEqual, "web-a")
+				So(s[0].Subnet, ShouldEqual, "")
+			})
+		})
+	})
+
+	Convey("Given a network with a single availability zone", t, func() {
+		n := Network{Name: "web", Subnet: "10.1.0.0/24", AvailabilityZone: "eu-west-1a"}
+
+		Convey("When getting its subnets", func() {
+			s := n.Subnets()
+			Convey("Then it should return the network unchanged", func() {
+				So(len(s), ShouldEqual, 1)
+				So(s[0].Name, ShouldEqual, "web")
+				So(s[0].Subnet, ShouldEqual, "10.1.0.0/24")
+			})
+		})
+	})
+}
+
+func TestNetworkValidateChange(t *testing.T) {
+	Convey("Given a network from a previous build", t, func() {
+		on := Network{Name: "web", Subnet: "10.1.0.0/24", AvailabilityZone: "eu-west-1a"}
+		n := on
+
+		Convey("When its range is changed", func() {
+			n.Subnet = "10.1.1.0/24"
+
+			Convey("And no components are placed on it", func() {
+				Convey("Then it should not return an error", func() {
+					So(n.ValidateChange(&on, false), ShouldBeNil)
+				})
+			})
+
+			Convey("And instances are placed on it", func() {
+				d := Definition{Networks: []Network{n}, Instances: []Instance{{Name: "app", Network: "web"}}}
+				err := d.ValidateChanges(&Definition{Networks: []Network{on}})
+				Convey("Then it should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.(ValidationErrors)[0].Field, ShouldEqual, "subnet")
+					So(err.Error(), ShouldEqual, "Network range can't be changed from 10.1.0.0/24 to 10.1.1.0/24 while other components are placed on it")
+				})
+			})
+		})
+
+		Convey("When its availability zone is changed while it is used", func() {
+			n.AvailabilityZone = "eu-west-1b"
+			err := n.ValidateChange(&on, true)
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Network availability zone can't be changed from eu-west-1a to eu-west-1b while other components are placed on it")
+			})
+		})
+	})
+}
+
+func TestNetworkValidateZonesChange(t *testing.T) {
+	Convey("Given a network across availability zones from a previous build", t, func() {
+		on := Network{Name: "web", Subnet: "10.1.0.0/24", AvailabilityZones: []string{"eu-west-1a", "eu-west-1b"}}
+		n := on
+
+		Convey("When an availability zone is added while it is used", func() {
+			n.AvailabilityZones = []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"}
+			err := n.ValidateChange(&on, true)
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Network availability zones can't be changed from [eu-west-1a, eu-west-1b] to [eu-west-1a, eu-west-1b, eu-west-1c] while other components are placed on it")
+			})
+		})
+
+		Convey("When the availability zones are unchanged", func() {
+			Convey("Then it should not return an error", func() {
+				So(n.ValidateChange(&on, true), ShouldBeNil)
+			})
+		})
+	})
+}
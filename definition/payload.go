@@ -11,18 +11,47 @@ import "encoding/json"
 // It has all the info needed to build the message that is going to be sent
 // to the FSM over NATS.
 type Payload struct {
-	ServiceID  string     `json:"id"`
-	PrevID     string     `json:"previous_id"`
-	Datacenter Datacenter `json:"datacenter"`
-	Client     Client     `json:"client"`
-	Service    Definition `json:"service"`
+	ServiceID  string                 `json:"id"`
+	PrevID     string                 `json:"previous_id"`
+	Datacenter Datacenter             `json:"datacenter"`
+	Client     Client                 `json:"client"`
+	Service    Definition             `json:"service"`
+	Variables  map[string]interface{} `json:"variables,omitempty"`
 }
 
 // PayloadFromJSON returns a definition payload from json
 func PayloadFromJSON(data []byte) (*Payload, error) {
 	var p Payload
 
-	err := json.Unmarshal(data, &p)
+	var raw map[string]json.RawMessage
+	var overrides struct {
+		Variables map[string]interface{} `json:"variables"`
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	// Interpolate the definitions variables before it is validated
+	if service, ok := raw["service"]; ok && string(service) != "null" {
+		raw["service"], err = ResolveVariables(service, overrides.Variables)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err = json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = json.Unmarshal(data, &p)
 	if err != nil {
 		return nil, err
 	}
@@ -5,8 +5,6 @@
 package definition
 
 import (
-	"errors"
-	"fmt"
 	"strings"
 	"unicode"
 )
@@ -35,71 +33,56 @@ type RDSCluster struct {
 	FinalSnapshot     bool      `json:"final_snapshot"`
 }
 
-// Validate the rds cluster
+// Validate the rds cluster, returning every field that is not valid
 func (r *RDSCluster) Validate(networks []Network, securitygroups []SecurityGroup) error {
-	if r.Name == "" {
-		return errors.New("RDS Cluster name should not be null")
-	}
+	var errs ValidationErrors
 
-	if len(r.Name) > 255 {
-		return errors.New("RDS Cluster name should not exceed 255 characters")
+	if r.Name == "" {
+		errs.addField(fieldError("name", "RDS Cluster name should not be null"))
+	} else if len(r.Name) > 255 {
+		errs.addField(fieldError("name", "RDS Cluster name should not exceed 255 characters"))
 	}
 
 	if r.Engine == "" {
-		return errors.New("RDS Cluster engine type should not be null")
+		errs.addField(fieldError("engine", "RDS Cluster engine type should not be null"))
 	}
 
 	if r.ReplicationSource != "" {
 		if len(r.ReplicationSource) < 12 || r.ReplicationSource[:12] != "arn:aws:rds:" {
-			return errors.New("RDS Cluster replication source should be a valid amazon resource name (ARN), i.e. 'arn:aws:rds:us-east-1:123456789012:cluster:my-aurora-cluster'")
+			errs.addField(fieldError("replication_source", "RDS Cluster replication source should be a valid amazon resource name (ARN), i.e. 'arn:aws:rds:us-east-1:123456789012:cluster:my-aurora-cluster'"))
 		}
 	}
 
 	if r.DatabaseName == "" {
-		return errors.New("RDS Cluster database name should not be null")
-	}
-
-	if len(r.DatabaseName) > 64 {
-		return errors.New("RDS Cluster database name should not exceed 64 characters")
-	}
-
-	for _, c := range r.DatabaseName {
-		if unicode.IsLetter(c) != true && unicode.IsNumber(c) != true {
-			return errors.New("RDS Cluster database name can only contain alphanumeric characters")
+		errs.addField(fieldError("database_name", "RDS Cluster database name should not be null"))
+	} else if len(r.DatabaseName) > 64 {
+		errs.addField(fieldError("database_name", "RDS Cluster database name should not exceed 64 characters"))
+	} else {
+		for _, c := range r.DatabaseName {
+			if unicode.IsLetter(c) != true && unicode.IsNumber(c) != true {
+				errs.addField(fieldError("database_name", "RDS Cluster database name can only contain alphanumeric characters"))
+				break
+			}
 		}
 	}
 
 	if r.DatabaseUsername == "" {
-		return errors.New("RDS Cluster database username should not be null")
-	}
-
-	if len(r.DatabaseUsername) > 16 {
-		return errors.New("RDS Cluster database username should not exceed 16 characters")
+		errs.addField(fieldError("database_username", "RDS Cluster database username should not be null"))
+	} else if len(r.DatabaseUsername) > 16 {
+		errs.addField(fieldError("database_username", "RDS Cluster database username should not exceed 16 characters"))
 	}
 
-	if r.DatabasePassword == "" {
-		return errors.New("RDS Cluster database password should not be null")
-	}
-
-	if len(r.DatabasePassword) < 8 || len(r.DatabasePassword) > 41 {
-		return errors.New("RDS Cluster database password should be between 8 and 41 characters")
-	}
-
-	for _, c := range r.DatabasePassword {
-		if unicode.IsSymbol(c) || unicode.IsMark(c) {
-			return fmt.Errorf("RDS Cluster database password contains an offending character: '%c'", c)
-		}
-	}
+	errs.addField(validateDatabasePassword("RDS Cluster", r.DatabasePassword))
 
 	if r.Port != nil {
 		if *r.Port < 1150 || *r.Port > 65535 {
-			return errors.New("RDS Cluster port number should be between 1150 and 65535")
+			errs.addField(fieldError("port", "RDS Cluster port number should be between 1150 and 65535"))
 		}
 	}
 
 	if r.Backups.Retention != nil {
 		if *r.Backups.Retention < 1 || *r.Backups.Retention > 35 {
-			return errors.New("RDS Cluster backup retention should be between 1 and 35 days")
+			errs.addField(fieldError("backups.retention", "RDS Cluster backup retention should be between 1 and 35 days"))
 		}
 	}
 
@@ -107,45 +90,43 @@ func (r *RDSCluster) Validate(networks []Network, securitygroups []SecurityGroup
 		parts := strings.Split(r.Backups.Window, "-")
 
 		err := validateTimeFormat(parts[0])
-		if err != nil {
-			return errors.New("RDS Cluster backup window: " + err.Error())
+		if err == nil {
+			err = validateTimeFormat(parts[1])
 		}
-
-		err = validateTimeFormat(parts[1])
 		if err != nil {
-			return errors.New("RDS Cluster backup window: " + err.Error())
+			errs.addField(fieldError("backups.window", "RDS Cluster backup window: "+err.Error()))
 		}
 	}
 
 	if mwerr := validateTimeWindow(r.MaintenanceWindow); r.MaintenanceWindow != "" && mwerr != nil {
-		return fmt.Errorf("RDS Cluster maintenance window: %s", mwerr.Error())
+		errs.addField(fieldErrorf("maintenance_window", "RDS Cluster maintenance window: %s", mwerr.Error()))
 	}
 
 	for _, nw := range r.Networks {
 		if isNetwork(networks, nw) != true {
-			return fmt.Errorf("RDS Cluster network '%s' does not exist", nw)
+			errs.addField(fieldErrorf("networks", "RDS Cluster network '%s' does not exist", nw))
 		}
 	}
 
 	for _, sg := range r.SecurityGroups {
 		if isSecurityGroup(securitygroups, sg) != true {
-			return fmt.Errorf("RDS Cluster security group '%s' does not exist", sg)
+			errs.addField(fieldErrorf("security_groups", "RDS Cluster security group '%s' does not exist", sg))
 		}
 	}
 
 	if len(r.Networks) > 0 || len(r.AvailabilityZones) > 0 {
-		if len(r.Networks) < 2 || r.meetsNetworkAZRequirement(networks) != true {
-			return errors.New("RDS Cluster should specify at least two networks in different availability zones if no cluster availability zone is specified")
+		if countSubnets(networks, r.Networks) < 2 || r.meetsNetworkAZRequirement(networks) != true {
+			errs.addField(fieldError("networks", "RDS Cluster should specify at least two networks in different availability zones if no cluster availability zone is specified"))
 		}
 
 		for _, az := range r.AvailabilityZones {
 			if r.hasAvailabilityZone(networks, az) != true {
-				return fmt.Errorf("RDS Cluster has no network specified for the availability zone '%s'", az)
+				errs.addField(fieldErrorf("availability_zones", "RDS Cluster has no network specified for the availability zone '%s'", az))
 			}
 		}
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
 
 func (r *RDSCluster) meetsNetworkAZRequirement(networks []Network) bool {
@@ -153,7 +134,9 @@ func (r *RDSCluster) meetsNetworkAZRequirement(networks []Network) bool {
 	for _, cn := range r.Networks {
 		for _, n := range networks {
 			if n.Name == cn {
-				azs = appendUnique(azs, n.AvailabilityZone)
+				for _, az := range n.Zones() {
+					azs = appendUnique(azs, az)
+				}
 			}
 		}
 	}
@@ -168,7 +151,7 @@ func (r *RDSCluster) meetsNetworkAZRequirement(networks []Network) bool {
 func (r *RDSCluster) hasAvailabilityZone(networks []Network, az string) bool {
 	for _, cn := range r.Networks {
 		for _, n := range networks {
-			if n.Name == cn && n.AvailabilityZone == az {
+			if n.Name == cn && isOneOf(n.Zones(), az) {
 				return true
 			}
 		}
@@ -48,6 +48,23 @@ func TestRDSClusterValidate(t *testing.T) {
 			FinalSnapshot:     true,
 		}
 
+		Convey("With multiple invalid fields", func() {
+			r.Name = ""
+			r.Engine = ""
+			r.DatabaseName = ""
+			Convey("When validating the rds cluster", func() {
+				err := r.Validate(nws, sgs)
+				Convey("Then should return an error for every invalid field", func() {
+					So(err, ShouldNotBeNil)
+					errs := err.(ValidationErrors)
+					So(len(errs), ShouldEqual, 3)
+					So(errs[0].Field, ShouldEqual, "name")
+					So(errs[1].Field, ShouldEqual, "engine")
+					So(errs[2].Field, ShouldEqual, "database_name")
+				})
+			})
+		})
+
 		Convey("With an invalid name", func() {
 			r.Name = ""
 			Convey("When validating the rds cluster", func() {
@@ -158,6 +175,27 @@ func TestRDSClusterValidate(t *testing.T) {
 			})
 		})
 
+		Convey("With a database password secret that can't be resolved yet", func() {
+			r.DatabasePassword = "secret://env/RDS_CLUSTER_TEST_MISSING"
+			Convey("When validating the rds cluster", func() {
+				err := r.Validate(nws, sgs)
+				Convey("Then it should not resolve the secret", func() {
+					So(err, ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("With a database password secret reference that is not valid", func() {
+			r.DatabasePassword = "secret://env"
+			Convey("When validating the rds cluster", func() {
+				err := r.Validate(nws, sgs)
+				Convey("Then should return an error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "RDS Cluster database password secret reference is not valid: secret reference must take the form of secret://provider/key")
+				})
+			})
+		})
+
 		Convey("With a database password that exeeds the maximum length", func() {
 			r.DatabasePassword = "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx"
 			Convey("When validating the rds cluster", func() {
@@ -307,7 +345,7 @@ func TestRDSClusterValidate(t *testing.T) {
 				err := r.Validate(nws, sgs)
 				Convey("Then should return an error", func() {
 					So(err, ShouldNotBeNil)
-					So(err.Error(), ShouldEqual, "RDS Cluster network 'fake' does not exist")
+					So(err.(ValidationErrors)[0].Message, ShouldEqual, "RDS Cluster network 'fake' does not exist")
 				})
 			})
 		})
@@ -5,8 +5,6 @@
 package definition
 
 import (
-	"errors"
-	"fmt"
 	"strings"
 	"unicode"
 )
@@ -54,143 +52,121 @@ type RDSInstance struct {
 	Timezone          string     `json:"timezone"`
 }
 
-// Validate the rds cluster
+// Validate the rds instance, returning every field that is not valid
 func (r *RDSInstance) Validate(networks []Network, securitygroups []SecurityGroup, clusters []RDSCluster) error {
-	if r.Name == "" {
-		return errors.New("RDS Instance name should not be null")
-	}
+	var errs ValidationErrors
 
-	if len(r.Name) > 255 {
-		return errors.New("RDS Instance name should not exceed 255 characters")
+	if r.Name == "" {
+		errs.addField(fieldError("name", "RDS Instance name should not be null"))
+	} else if len(r.Name) > 255 {
+		errs.addField(fieldError("name", "RDS Instance name should not exceed 255 characters"))
 	}
 
 	if r.Size == "" {
-		return errors.New("RDS Instance size should not be null")
+		errs.addField(fieldError("size", "RDS Instance size should not be null"))
+	} else if !strings.HasPrefix(r.Size, "db.") {
+		errs.addField(fieldError("size", "RDS Instance size should be a valid resource size. i.e. 'db.r3.large'"))
 	}
 
-	if r.Size[:3] != "db." {
-		return errors.New("RDS Instance size should be a valid resource size. i.e. 'db.r3.large'")
-	}
-
-	err := r.validateReplication()
-	if err != nil {
-		return err
-	}
+	errs.addField(r.validateReplication())
 
 	cluster := findRDSCluster(clusters, r.Cluster)
 	if r.Cluster != "" && cluster == nil {
-		return fmt.Errorf("RDS Instance cluster identifier '%s' does not exist", r.Cluster)
-	}
-
-	err = r.validateDatabase(cluster)
-	if err != nil {
-		return err
-	}
-
-	err = r.validateEngine(cluster)
-	if err != nil {
-		return err
-	}
-
-	err = r.validatePort(cluster)
-	if err != nil {
-		return err
+		errs.addField(fieldErrorf("cluster", "RDS Instance cluster identifier '%s' does not exist", r.Cluster))
 	}
 
-	err = r.validateStorage()
-	if err != nil {
-		return err
-	}
-
-	err = r.validateBackups()
-	if err != nil {
-		return err
-	}
-
-	err = r.validateOther(cluster)
-	if err != nil {
-		return err
-	}
+	errs.addField(r.validateDatabase(cluster))
+	errs.addField(r.validateEngine(cluster))
+	errs.addField(r.validatePort(cluster))
+	errs.addField(r.validateStorage())
+	errs.addField(r.validateBackups())
+	errs.addField(r.validateOther(cluster))
 
 	for _, nw := range r.Networks {
 		if isNetwork(networks, nw) != true {
-			return fmt.Errorf("RDS Instance network '%s' does not exist", nw)
+			errs.addField(fieldErrorf("networks", "RDS Instance network '%s' does not exist", nw))
 		}
 	}
 
 	for _, sg := range r.SecurityGroups {
 		if isSecurityGroup(securitygroups, sg) != true {
-			return fmt.Errorf("RDS Instance security group '%s' does not exist", sg)
+			errs.addField(fieldErrorf("security_groups", "RDS Instance security group '%s' does not exist", sg))
 		}
 	}
 
 	if r.Public != true && r.Cluster == "" {
 		if r.AvailabilityZone != "" && r.hasAvailabilityZone(networks, r.AvailabilityZone) != true {
-			return fmt.Errorf("RDS Instance has no network specified for the availability zone '%s'", r.AvailabilityZone)
+			errs.addField(fieldErrorf("availability_zone", "RDS Instance has no network specified for the availability zone '%s'", r.AvailabilityZone))
 		}
 
-		if len(r.Networks) < 2 || r.meetsNetworkAZRequirement(networks) != true {
-			return errors.New("RDS Instance should specify at least two networks in different availability zones if no cluster availability zone is specified")
+		if countSubnets(networks, r.Networks) < 2 || r.meetsNetworkAZRequirement(networks) != true {
+			errs.addField(fieldError("networks", "RDS Instance should specify at least two networks in different availability zones if no cluster availability zone is specified"))
 		}
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
 
 func (r *RDSInstance) validateReplication() error {
-	if r.ReplicationSource != "" {
-		if r.Engine != "" {
-			return errors.New("RDS Instance must not specify an engine if a replication source is set")
-		}
+	var errs ValidationErrors
 
-		if r.EngineVersion != "" {
-			return errors.New("RDS Instance must not specify an engine version if a replication source is set")
-		}
+	if r.ReplicationSource == "" {
+		return nil
+	}
 
-		if r.Storage.Size != nil {
-			return errors.New("RDS Instance must not specify storage size if a replication source is set")
-		}
+	if r.Engine != "" {
+		errs.addField(fieldError("engine", "RDS Instance must not specify an engine if a replication source is set"))
+	}
 
-		if r.Cluster != "" {
-			return errors.New("RDS Instance must not specify a cluster if a replication source is set")
-		}
+	if r.EngineVersion != "" {
+		errs.addField(fieldError("engine_version", "RDS Instance must not specify an engine version if a replication source is set"))
+	}
 
-		if r.MultiAZ == true {
-			return errors.New("RDS Instance must not specify multi az standby instance if a replication source is set")
-		}
+	if r.Storage.Size != nil {
+		errs.addField(fieldError("storage.size", "RDS Instance must not specify storage size if a replication source is set"))
+	}
 
-		if r.PromotionTier != nil {
-			return errors.New("RDS Instance must not specify promotion tier if a replication source is set")
-		}
+	if r.Cluster != "" {
+		errs.addField(fieldError("cluster", "RDS Instance must not specify a cluster if a replication source is set"))
+	}
 
-		if r.DatabaseName != "" {
-			return errors.New("RDS Instance must not specify database name if a replication source is set")
-		}
+	if r.MultiAZ == true {
+		errs.addField(fieldError("multi_az", "RDS Instance must not specify multi az standby instance if a replication source is set"))
+	}
 
-		if r.DatabaseUsername != "" {
-			return errors.New("RDS Instance must not specify database username if a replication source is set")
-		}
+	if r.PromotionTier != nil {
+		errs.addField(fieldError("promotion_tier", "RDS Instance must not specify promotion tier if a replication source is set"))
+	}
 
-		if r.DatabasePassword != "" {
-			return errors.New("RDS Instance must not specify database password if a replication source is set")
-		}
+	if r.DatabaseName != "" {
+		errs.addField(fieldError("database_name", "RDS Instance must not specify database name if a replication source is set"))
+	}
 
-		if r.License != "" {
-			return errors.New("RDS Instance must not specify a license type if a replication source is set")
-		}
+	if r.DatabaseUsername != "" {
+		errs.addField(fieldError("database_username", "RDS Instance must not specify database username if a replication source is set"))
+	}
 
-		if r.Timezone != "" {
-			return errors.New("RDS Instance must not specify a timezone if a replication source is set")
-		}
+	if r.DatabasePassword != "" {
+		errs.addField(fieldError("database_password", "RDS Instance must not specify database password if a replication source is set"))
 	}
 
-	return nil
+	if r.License != "" {
+		errs.addField(fieldError("license", "RDS Instance must not specify a license type if a replication source is set"))
+	}
+
+	if r.Timezone != "" {
+		errs.addField(fieldError("timezone", "RDS Instance must not specify a timezone if a replication source is set"))
+	}
+
+	return errs.errorOrNil()
 }
 
 func (r *RDSInstance) validateBackups() error {
+	var errs ValidationErrors
+
 	if r.Backups.Retention != nil {
 		if *r.Backups.Retention < 1 || *r.Backups.Retention > 35 {
-			return errors.New("RDS Instance backup retention should be between 1 and 35 days")
+			errs.addField(fieldError("backups.retention", "RDS Instance backup retention should be between 1 and 35 days"))
 		}
 	}
 
@@ -198,27 +174,25 @@ func (r *RDSInstance) validateBackups() error {
 		parts := strings.Split(r.Backups.Window, "-")
 
 		err := validateTimeFormat(parts[0])
-		if err != nil {
-			return errors.New("RDS Instance backup window: " + err.Error())
+		if err == nil {
+			err = validateTimeFormat(parts[1])
 		}
-
-		err = validateTimeFormat(parts[1])
 		if err != nil {
-			return errors.New("RDS Instance backup window: " + err.Error())
+			errs.addField(fieldError("backups.window", "RDS Instance backup window: "+err.Error()))
 		}
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
 
 func (r *RDSInstance) validatePort(cluster *RDSCluster) error {
 	if cluster != nil && r.Port != nil {
-		return fmt.Errorf("RDS Instance port should be set on cluster")
+		return fieldError("port", "RDS Instance port should be set on cluster")
 	}
 
 	if r.Port != nil {
 		if *r.Port < 1150 || *r.Port > 65535 {
-			return errors.New("RDS Instance port number should be between 1150 and 65535")
+			return fieldError("port", "RDS Instance port number should be between 1150 and 65535")
 		}
 	}
 
@@ -226,138 +200,130 @@ func (r *RDSInstance) validatePort(cluster *RDSCluster) error {
 }
 
 func (r *RDSInstance) validateDatabase(cluster *RDSCluster) error {
+	var errs ValidationErrors
+
 	if cluster != nil {
 		if r.DatabaseName != "" {
-			return errors.New("RDS Instance database name should be set on cluster")
+			errs.addField(fieldError("database_name", "RDS Instance database name should be set on cluster"))
 		}
 
 		if r.DatabaseUsername != "" {
-			return errors.New("RDS Instance database username should be set on cluster")
+			errs.addField(fieldError("database_username", "RDS Instance database username should be set on cluster"))
 		}
 
 		if r.DatabasePassword != "" {
-			return errors.New("RDS Instance database password should be set on cluster")
-		}
-	} else {
-		if r.DatabaseName == "" {
-			return errors.New("RDS Instance database name should not be null")
-		}
-
-		if r.DatabaseUsername == "" {
-			return errors.New("RDS Instance database username should not be null")
-		}
-
-		if r.DatabasePassword == "" {
-			return errors.New("RDS Instance database password should not be null")
+			errs.addField(fieldError("database_password", "RDS Instance database password should be set on cluster"))
 		}
 
-		if len(r.DatabaseName) > 64 {
-			return errors.New("RDS Instance database name should not exceed 64 characters")
-		}
+		return errs.errorOrNil()
+	}
 
+	if r.DatabaseName == "" {
+		errs.addField(fieldError("database_name", "RDS Instance database name should not be null"))
+	} else if len(r.DatabaseName) > 64 {
+		errs.addField(fieldError("database_name", "RDS Instance database name should not exceed 64 characters"))
+	} else {
 		for _, c := range r.DatabaseName {
 			if unicode.IsLetter(c) != true && unicode.IsNumber(c) != true {
-				return errors.New("RDS Instance database name can only contain alphanumeric characters")
+				errs.addField(fieldError("database_name", "RDS Instance database name can only contain alphanumeric characters"))
+				break
 			}
 		}
+	}
 
-		if len(r.DatabaseUsername) > 16 {
-			return errors.New("RDS Instance database username should not exceed 16 characters")
-		}
-
-		if r.DatabasePassword == "" {
-			return errors.New("RDS Instance database password should not be null")
-		}
-
-		if len(r.DatabasePassword) < 8 || len(r.DatabasePassword) > 41 {
-			return errors.New("RDS Instance database password should be between 8 and 41 characters")
-		}
-
-		for _, c := range r.DatabasePassword {
-			if unicode.IsSymbol(c) || unicode.IsMark(c) {
-				return fmt.Errorf("RDS Instance database password contains an offending character: '%c'", c)
-			}
-		}
+	if r.DatabaseUsername == "" {
+		errs.addField(fieldError("database_username", "RDS Instance database username should not be null"))
+	} else if len(r.DatabaseUsername) > 16 {
+		errs.addField(fieldError("database_username", "RDS Instance database username should not exceed 16 characters"))
 	}
 
-	return nil
+	errs.addField(validateDatabasePassword("RDS Instance", r.DatabasePassword))
+
+	return errs.errorOrNil()
 }
 
 func (r *RDSInstance) validateEngine(cluster *RDSCluster) error {
+	var errs ValidationErrors
+
 	if cluster != nil {
 		if r.Engine != "" {
-			return fmt.Errorf("RDS Instance engine type should be set on cluster")
+			errs.addField(fieldError("engine", "RDS Instance engine type should be set on cluster"))
 		}
 
 		if r.EngineVersion != "" {
-			return fmt.Errorf("RDS Instance engine version should be set on cluster")
-		}
-	} else {
-		if r.Engine == "" {
-			return errors.New("RDS Instance engine type should not be null")
+			errs.addField(fieldError("engine_version", "RDS Instance engine version should be set on cluster"))
 		}
+	} else if r.Engine == "" {
+		errs.addField(fieldError("engine", "RDS Instance engine type should not be null"))
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
 
 func (r *RDSInstance) validateStorage() error {
-	if r.Engine != EngineTypeAurora {
-		if r.Storage.Type != "" && isOneOf(StorageTypes, r.Storage.Type) != true {
-			return errors.New("RDS Instance storage type must be either 'standard', 'gp2' or 'io1'")
-		}
-		if r.Storage.Size != nil {
-			if *r.Storage.Size < 5 || *r.Storage.Size > 6144 {
-				return errors.New("RDS Instance storage size must be between 5 - 6144 GB")
-			}
+	var errs ValidationErrors
+
+	if r.Engine == EngineTypeAurora {
+		if r.Storage.Type != "" || r.Storage.Size != nil || r.Storage.Iops != nil {
+			return fieldError("storage", "RDS Instance storage options cannot be set if the engine type is 'aurora'")
 		}
-		if r.Storage.Iops != nil {
-			if (*r.Storage.Iops % 1000) != 0 {
-				return errors.New("RDS Instance storage iops must be a multiple of 1000")
-			}
+		return nil
+	}
+
+	if r.Storage.Type != "" && isOneOf(StorageTypes, r.Storage.Type) != true {
+		errs.addField(fieldError("storage.type", "RDS Instance storage type must be either 'standard', 'gp2' or 'io1'"))
+	}
+
+	if r.Storage.Size != nil {
+		if *r.Storage.Size < 5 || *r.Storage.Size > 6144 {
+			errs.addField(fieldError("storage.size", "RDS Instance storage size must be between 5 - 6144 GB"))
 		}
-	} else {
-		if r.Storage.Type != "" || r.Storage.Size != nil || r.Storage.Iops != nil {
-			return errors.New("RDS Instance storage options cannot be set if the engine type is 'aurora'")
+	}
+
+	if r.Storage.Iops != nil {
+		if (*r.Storage.Iops % 1000) != 0 {
+			errs.addField(fieldError("storage.iops", "RDS Instance storage iops must be a multiple of 1000"))
 		}
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
 
 func (r *RDSInstance) validateOther(cluster *RDSCluster) error {
+	var errs ValidationErrors
+
 	if r.PromotionTier != nil {
 		if r.Engine != EngineTypeAurora {
-			return errors.New("RDS Instance promotion tier should only be specified when using the aurora engine")
+			errs.addField(fieldError("promotion_tier", "RDS Instance promotion tier should only be specified when using the aurora engine"))
 		}
 		if *r.PromotionTier < 0 || *r.PromotionTier > 15 {
-			return errors.New("RDS Instance promotion tier should be between 0 - 15")
+			errs.addField(fieldError("promotion_tier", "RDS Instance promotion tier should be between 0 - 15"))
 		}
 	}
 
 	if r.AvailabilityZone != "" && r.MultiAZ {
-		return errors.New("RDS Instance cannot specify both an availability zone and a multi az standby instance")
+		errs.addField(fieldError("availability_zone", "RDS Instance cannot specify both an availability zone and a multi az standby instance"))
 	}
 
 	if mwerr := validateTimeWindow(r.MaintenanceWindow); r.MaintenanceWindow != "" && mwerr != nil {
-		return fmt.Errorf("RDS Instance maintenance window: %s", mwerr.Error())
+		errs.addField(fieldErrorf("maintenance_window", "RDS Instance maintenance window: %s", mwerr.Error()))
 	}
 
 	if r.Public == false && len(r.Networks) < 1 && cluster == nil {
-		return errors.New("RDS Instance should specify at least one network if not set to public")
+		errs.addField(fieldError("networks", "RDS Instance should specify at least one network if not set to public"))
 	}
 
 	if r.Engine != EngineTypeAurora && r.Engine != "" && isOneOf(Licenses, r.License) != true {
-		return errors.New("RDS Instance license must be one of 'license-included', 'bring-your-own-license', 'general-public-license'")
+		errs.addField(fieldError("license", "RDS Instance license must be one of 'license-included', 'bring-your-own-license', 'general-public-license'"))
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
 
 func (r *RDSInstance) hasAvailabilityZone(networks []Network, az string) bool {
 	for _, cn := range r.Networks {
 		for _, n := range networks {
-			if n.Name == cn && n.AvailabilityZone == az {
+			if n.Name == cn && isOneOf(n.Zones(), az) {
 				return true
 			}
 		}
@@ -370,7 +336,9 @@ func (r *RDSInstance) meetsNetworkAZRequirement(networks []Network) bool {
 	for _, cn := range r.Networks {
 		for _, n := range networks {
 			if n.Name == cn {
-				azs = appendUnique(azs, n.AvailabilityZone)
+				for _, az := range n.Zones() {
+					azs = appendUnique(azs, az)
+				}
 			}
 		}
 	}
@@ -314,7 +314,7 @@ func TestRDSInstanceValidate(t *testing.T) {
 				err := r.Validate(nws, sgs, cls)
 				Convey("Then should return an error", func() {
 					So(err, ShouldNotBeNil)
-					So(err.Error(), ShouldEqual, "RDS Instance network 'fake' does not exist")
+					So(err.(ValidationErrors)[0].Message, ShouldEqual, "RDS Instance network 'fake' does not exist")
 				})
 			})
 		})
@@ -325,7 +325,7 @@ func TestRDSInstanceValidate(t *testing.T) {
 				err := r.Validate(nws, sgs, cls)
 				Convey("Then should return an error", func() {
 					So(err, ShouldNotBeNil)
-					So(err.Error(), ShouldEqual, "RDS Instance should specify at least one network if not set to public")
+					So(err.(ValidationErrors)[0].Message, ShouldEqual, "RDS Instance should specify at least one network if not set to public")
 				})
 			})
 		})
@@ -428,7 +428,7 @@ func TestRDSInstanceValidate(t *testing.T) {
 				err := r.Validate(nws, sgs, cls)
 				Convey("Then should return an error", func() {
 					So(err, ShouldNotBeNil)
-					So(err.Error(), ShouldEqual, "RDS Instance database name should be set on cluster")
+					So(err.(ValidationErrors)[0].Message, ShouldEqual, "RDS Instance database name should be set on cluster")
 				})
 			})
 		})
@@ -442,7 +442,7 @@ func TestRDSInstanceValidate(t *testing.T) {
 				err := r.Validate(nws, sgs, cls)
 				Convey("Then should return an error", func() {
 					So(err, ShouldNotBeNil)
-					So(err.Error(), ShouldEqual, "RDS Instance database username should be set on cluster")
+					So(err.(ValidationErrors)[0].Message, ShouldEqual, "RDS Instance database username should be set on cluster")
 				})
 			})
 		})
@@ -456,7 +456,7 @@ func TestRDSInstanceValidate(t *testing.T) {
 				err := r.Validate(nws, sgs, cls)
 				Convey("Then should return an error", func() {
 					So(err, ShouldNotBeNil)
-					So(err.Error(), ShouldEqual, "RDS Instance database password should be set on cluster")
+					So(err.(ValidationErrors)[0].Message, ShouldEqual, "RDS Instance database password should be set on cluster")
 				})
 			})
 		})
@@ -0,0 +1,30 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"encoding/json"
+
+	"github.com/ernestio/aws-definition-mapper/secret"
+)
+
+// Redacted returns a copy of the definition with every secret redacted.
+// It should be used for anything that is logged or stored
+func (d *Definition) Redacted() (*Definition, error) {
+	var r Definition
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+
+	secret.Redact(&r)
+
+	return &r, nil
+}
@@ -0,0 +1,39 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ernestio/aws-definition-mapper/secret"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRedacted(t *testing.T) {
+	Convey("Given a definition with secrets", t, func() {
+		d := Definition{Name: "service"}
+		d.Instances = append(d.Instances, Instance{Name: "web", UserData: "export TOKEN=usrdatatoken"})
+		d.RDSInstances = append(d.RDSInstances, RDSInstance{Name: "db", DatabasePassword: "plainpassword"})
+		d.RDSClusters = append(d.RDSClusters, RDSCluster{Name: "cluster", DatabasePassword: "secret://env/DB_PASSWORD"})
+
+		Convey("When redacting it", func() {
+			r, err := d.Redacted()
+			So(err, ShouldBeNil)
+
+			data, _ := json.Marshal(r)
+			Convey("Then no secret should be present in clear text", func() {
+				So(string(data), ShouldNotContainSubstring, "usrdatatoken")
+				So(string(data), ShouldNotContainSubstring, "plainpassword")
+				So(r.Instances[0].UserData, ShouldEqual, secret.REDACTED)
+				So(r.RDSClusters[0].DatabasePassword, ShouldEqual, "secret://env/DB_PASSWORD")
+			})
+
+			Convey("Then the original definition should not be changed", func() {
+				So(d.RDSInstances[0].DatabasePassword, ShouldEqual, "plainpassword")
+			})
+		})
+	})
+}
@@ -37,17 +37,19 @@ type Route53Zone struct {
 
 // Validate checks if a Route53Zone is valid
 func (z *Route53Zone) Validate() error {
+	var errs ValidationErrors
+
 	if z.Name == "" {
-		return errors.New("Route53 zone name should not be null")
+		errs.addField(fieldError("name", "Route53 zone name should not be null"))
 	}
 
-	for _, record := range z.Records {
+	for x, record := range z.Records {
 		if record.Entry == "" {
-			return errors.New("Route53 record entry name should not be null")
+			errs.addField(fieldError(fmt.Sprintf("records[%d].entry", x), "Route53 record entry name should not be null"))
 		}
 
 		if !validDNSType(record.Type) {
-			return fmt.Errorf("Route53 record type '%s' is not a valid dns type. Please use one of [%s]", record.Type, strings.Join(DNSTypes, ", "))
+			errs.addField(fieldErrorf(fmt.Sprintf("records[%d].type", x), "Route53 record type '%s' is not a valid dns type. Please use one of [%s]", record.Type, strings.Join(DNSTypes, ", ")))
 		}
 
 		if len(record.Values) == 0 &&
@@ -55,37 +57,37 @@ func (z *Route53Zone) Validate() error {
 			len(record.Loadbalancers) == 0 &&
 			len(record.RDSInstances) == 0 &&
 			len(record.RDSClusters) == 0 {
-			return errors.New("Route53 record must specify a valid target [rds_instances, rds_clusters, instances or loadbalancers] or value")
+			errs.addField(fieldError(fmt.Sprintf("records[%d]", x), "Route53 record must specify a valid target [rds_instances, rds_clusters, instances or loadbalancers] or value"))
 		}
 
 		err := validateRecordTargets(&record)
 		if err != nil {
-			return err
+			errs.addField(withField(fmt.Sprintf("records[%d]", x), err))
 		}
 
 		// Todo: make this an aliased type
 		if len(record.Loadbalancers) > 0 && record.Type != CNAME {
-			return errors.New("Route53 record type must be CNAME when using loadbalancers as a target")
+			errs.addField(fieldError(fmt.Sprintf("records[%d].type", x), "Route53 record type must be CNAME when using loadbalancers as a target"))
 		}
 
 		if len(record.RDSInstances) > 0 && record.Type != CNAME {
-			return errors.New("Route53 record type must be CNAME when using rds_instances as a target")
+			errs.addField(fieldError(fmt.Sprintf("records[%d].type", x), "Route53 record type must be CNAME when using rds_instances as a target"))
 		}
 
 		if len(record.RDSClusters) > 0 && record.Type != CNAME {
-			return errors.New("Route53 record type must be CNAME when using rds_clusters as a target")
+			errs.addField(fieldError(fmt.Sprintf("records[%d].type", x), "Route53 record type must be CNAME when using rds_clusters as a target"))
 		}
 
 		if len(record.Instances) > 0 && record.Type != "A" {
-			return errors.New("Route53 record type must be A when using instances as a target")
+			errs.addField(fieldError(fmt.Sprintf("records[%d].type", x), "Route53 record type must be A when using instances as a target"))
 		}
 
 		if record.TTL == 0 {
-			return errors.New("Route53 record TTL must be greater than 0")
+			errs.addField(fieldError(fmt.Sprintf("records[%d].ttl", x), "Route53 record TTL must be greater than 0"))
 		}
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
 
 func validDNSType(t string) bool {
@@ -36,6 +36,23 @@ func TestRoute53Validate(t *testing.T) {
 				},
 			},
 		}
+		Convey("With multiple invalid fields", func() {
+			z.Name = ""
+			z.Records[0].Entry = ""
+			z.Records[1].TTL = 0
+			Convey("When validating the route53 zone", func() {
+				err := z.Validate()
+				Convey("Then it should return an error for every invalid field", func() {
+					So(err, ShouldNotBeNil)
+					errs := err.(ValidationErrors)
+					So(len(errs), ShouldEqual, 3)
+					So(errs[0].Field, ShouldEqual, "name")
+					So(errs[1].Field, ShouldEqual, "records[0].entry")
+					So(errs[2].Field, ShouldEqual, "records[1].ttl")
+				})
+			})
+		})
+
 		Convey("With a valid record", func() {
 			Convey("When validating the route53 zone", func() {
 				err := z.Validate()
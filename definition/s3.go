@@ -5,7 +5,6 @@
 package definition
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 	"unicode/utf8"
@@ -26,46 +25,45 @@ type S3 struct {
 	Grantees       []S3Grantee `json:"grantees,omitempty"`
 }
 
-// Validate checks if a Network is valid
+// Validate checks if an S3 bucket is valid, returning every field that is not valid
 func (s *S3) Validate() error {
+	var errs ValidationErrors
+
 	granteeTypes := []string{"id", "emailaddress", "uri", "canonicaluser"}
 	permissionTypes := []string{"full_control", "write", "write_acp", "read", "read_acp"}
 	aclTypes := []string{"private", "public-read", "public-read-write", "aws-exec-read", "authenticated-read", "log-delivery-write"}
 
 	if s.Name == "" {
-		return errors.New("S3 bucket name should not be null")
-	}
-
-	// Check if s3 bucket name is > 50 characters
-	if utf8.RuneCountInString(s.Name) > AWSMAXNAME {
-		return fmt.Errorf("S3 bucket name can't be greater than %d characters", AWSMAXNAME)
+		errs.addField(fieldError("name", "S3 bucket name should not be null"))
+	} else if utf8.RuneCountInString(s.Name) > AWSMAXNAME {
+		errs.addField(fieldErrorf("name", "S3 bucket name can't be greater than %d characters", AWSMAXNAME))
 	}
 
 	if s.BucketLocation == "" {
-		return errors.New("S3 bucket location should not be null")
+		errs.addField(fieldError("bucket_location", "S3 bucket location should not be null"))
 	}
 
 	if s.ACL != "" && len(s.Grantees) > 0 {
-		return errors.New("S3 bucket must specify either acl or grantees, not both")
+		errs.addField(fieldError("grantees", "S3 bucket must specify either acl or grantees, not both"))
 	}
 
 	if s.ACL != "" && isOneOf(aclTypes, s.ACL) == false {
-		return fmt.Errorf("S3 bucket ACL (%s) is not valid. Must be one of [%s]", s.ACL, strings.Join(aclTypes, " | "))
+		errs.addField(fieldErrorf("acl", "S3 bucket ACL (%s) is not valid. Must be one of [%s]", s.ACL, strings.Join(aclTypes, " | ")))
 	}
 
-	for _, g := range s.Grantees {
+	for x, g := range s.Grantees {
 		if isOneOf(granteeTypes, g.Type) == false {
-			return fmt.Errorf("S3 grantee type (%s) is invalid", g.Type)
+			errs.addField(fieldErrorf(fmt.Sprintf("grantees[%d].type", x), "S3 grantee type (%s) is invalid", g.Type))
 		}
 
 		if g.ID == "" {
-			return fmt.Errorf("S3 grantee id should not be null")
+			errs.addField(fieldError(fmt.Sprintf("grantees[%d].id", x), "S3 grantee id should not be null"))
 		}
 
 		if isOneOf(permissionTypes, g.Permissions) == false {
-			return fmt.Errorf("S3 grantee permissions (%s) is not valid. Must be one of [%s]", s.ACL, strings.Join(permissionTypes, " | "))
+			errs.addField(fieldErrorf(fmt.Sprintf("grantees[%d].permissions", x), "S3 grantee permissions (%s) is not valid. Must be one of [%s]", s.ACL, strings.Join(permissionTypes, " | ")))
 		}
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
@@ -24,6 +24,21 @@ func TestS3Validate(t *testing.T) {
 			},
 		}
 
+		Convey("With multiple invalid fields", func() {
+			s.Name = ""
+			s.BucketLocation = ""
+			Convey("When validating the s3 bucket", func() {
+				err := s.Validate()
+				Convey("Then it should return an error for every invalid field", func() {
+					So(err, ShouldNotBeNil)
+					errs := err.(ValidationErrors)
+					So(len(errs), ShouldEqual, 2)
+					So(errs[0].Field, ShouldEqual, "name")
+					So(errs[1].Field, ShouldEqual, "bucket_location")
+				})
+			})
+		})
+
 		Convey("With valid fields", func() {
 			Convey("When validating the s3 bucket", func() {
 				err := s.Validate()
@@ -5,7 +5,7 @@
 package definition
 
 import (
-	"errors"
+	"fmt"
 	"unicode/utf8"
 )
 
@@ -18,68 +18,77 @@ type SecurityGroup struct {
 
 // SecurityGroupRule ...
 type SecurityGroupRule struct {
-	IP       string `json:"ip"`
-	FromPort string `json:"from_port"`
-	ToPort   string `json:"to_port"`
-	Protocol string `json:"protocol"`
+	IP            string `json:"ip,omitempty"`
+	SecurityGroup string `json:"security_group,omitempty"`
+	FromPort      string `json:"from_port"`
+	ToPort        string `json:"to_port"`
+	Protocol      string `json:"protocol"`
 }
 
 // Validate security group
 func (sg *SecurityGroup) Validate(networks []Network) error {
+	var errs ValidationErrors
+
 	// Check if security group name is null
 	if sg.Name == "" {
-		return errors.New("Security Group name should not be null")
-	}
-
-	// Check if security group name is > 50 characters
-	if utf8.RuneCountInString(sg.Name) > AWSMAXNAME {
-		return errors.New("Security Group name can't be greater than 50 characters")
+		errs.addField(fieldError("name", "Security Group name should not be null"))
+	} else if utf8.RuneCountInString(sg.Name) > AWSMAXNAME {
+		errs.addField(fieldError("name", "Security Group name can't be greater than 50 characters"))
 	}
 
-	for _, rule := range sg.Ingress {
+	for x, rule := range sg.Ingress {
 		err := rule.Validate(networks)
 		if err != nil {
-			return err
+			errs.addField(withField(fmt.Sprintf("ingress[%d]", x), err))
 		}
 	}
 
-	for _, rule := range sg.Egress {
+	for x, rule := range sg.Egress {
 		err := rule.Validate(networks)
 		if err != nil {
-			return err
+			errs.addField(withField(fmt.Sprintf("egress[%d]", x), err))
 		}
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
 
 // Validate security group rule
 func (rule *SecurityGroupRule) Validate(networks []Network) error {
-	err := validateIP(rule.IP, "Security Group IP", networks)
-	if err != nil {
-		return err
+	var errs ValidationErrors
+
+	// A rule either targets an ip range or another security group
+	if rule.SecurityGroup != "" && rule.IP != "" {
+		errs.addField(fieldError("security_group", "Security Group rule must specify either an ip or a security group, not both"))
+	}
+
+	if rule.SecurityGroup == "" {
+		err := validateIP(rule.IP, "Security Group IP", networks)
+		if err != nil {
+			errs.addField(withField("ip", err))
+		}
 	}
 
 	// Validate FromPort Port
 	// Must be: [0 - 65535]
-	err = validatePort(rule.FromPort, "Security Group From")
+	err := validatePort(rule.FromPort, "Security Group From")
 	if err != nil {
-		return err
+		errs.addField(withField("from_port", err))
 	}
 
 	// Validate ToPort Port
 	// Must be: [0 - 65535]
 	err = validatePort(rule.ToPort, "Security Group To")
 	if err != nil {
-		return err
+		errs.addField(withField("to_port", err))
 	}
 
 	// Validate Protocol
 	// Must be one of: tcp | udp | icmp | any | tcp & udp
 	err = validateProtocol(rule.Protocol)
 	if err != nil {
-		return err
+		errs.addField(withField("protocol", err))
 	}
 
-	return nil
+	return errs.errorOrNil()
 }
@@ -16,6 +16,34 @@ func TestSecurityGroupRuleValidation(t *testing.T) {
 		n := []Network{Network{Name: "test", Subnet: "127.0.0.0/24"}}
 		r := SecurityGroupRule{IP: "127.0.0.1", FromPort: "0", ToPort: "65535", Protocol: "tcp"}
 
+		Convey("When I try to validate a rule with multiple invalid fields", func() {
+			r.IP = "invalid"
+			r.FromPort = "invalid"
+			r.Protocol = "invalid"
+			err := r.Validate(n)
+			Convey("Then it should return an error for every invalid field", func() {
+				So(err, ShouldNotBeNil)
+				errs := err.(ValidationErrors)
+				So(len(errs), ShouldEqual, 3)
+				So(errs[0].Field, ShouldEqual, "ip")
+				So(errs[1].Field, ShouldEqual, "from_port")
+				So(errs[2].Field, ShouldEqual, "protocol")
+			})
+		})
+
+		Convey("When I try to validate a security group with an invalid name and rule", func() {
+			r.ToPort = "99999"
+			sg := SecurityGroup{Ingress: []SecurityGroupRule{r}}
+			err := sg.Validate(n)
+			Convey("Then it should return an error for every invalid field", func() {
+				So(err, ShouldNotBeNil)
+				errs := err.(ValidationErrors)
+				So(len(errs), ShouldEqual, 2)
+				So(errs[0].Field, ShouldEqual, "name")
+				So(errs[1].Field, ShouldEqual, "ingress[0].to_port")
+			})
+		})
+
 		Convey("When I try to validate a rule with an invalid destination ip", func() {
 			r.IP = "invalid"
 			err := r.Validate(n)
@@ -41,6 +69,25 @@ func TestSecurityGroupRuleValidation(t *testing.T) {
 			})
 		})
 
+		Convey("When I try to validate a rule with a security group", func() {
+			r.IP = ""
+			r.SecurityGroup = "web-sg"
+			err := r.Validate(n)
+			Convey("Then it should not return an error", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When I try to validate a rule with both an ip and a security group", func() {
+			r.SecurityGroup = "web-sg"
+			err := r.Validate(n)
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.(ValidationErrors)[0].Field, ShouldEqual, "security_group")
+				So(err.Error(), ShouldEqual, "Security Group rule must specify either an ip or a security group, not both")
+			})
+		})
+
 		Convey("When From Port is not numeric", func() {
 			r.FromPort = "test"
 			err := r.Validate(n)
@@ -0,0 +1,159 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"net"
+)
+
+const (
+	// MINSUBNETSIZE : Largest subnet aws allows, as a prefix length
+	MINSUBNETSIZE = 16
+	// MAXSUBNETSIZE : Smallest subnet aws allows, as a prefix length
+	MAXSUBNETSIZE = 28
+)
+
+// AllocateSubnets assigns a subnet from the vpc range to every network that
+// only specifies a size. Subnets previously allocated to a network, keyed by
+// network name, are kept as long as they are still valid
+func (d *Definition) AllocateSubnets(previous map[string]string) {
+	_, vpc, err := net.ParseCIDR(d.VpcSubnet)
+	if err != nil {
+		return
+	}
+
+	var taken []*net.IPNet
+
+	for _, n := range d.Networks {
+		if _, nw, err := net.ParseCIDR(n.Subnet); err == nil {
+			taken = append(taken, nw)
+		}
+	}
+
+	// keep any previous allocations that still fit
+	for i, n := range d.Networks {
+		if n.Size < 1 || n.Subnet != "" {
+			continue
+		}
+
+		_, nw, err := net.ParseCIDR(previous[n.Name])
+		if err != nil {
+			continue
+		}
+
+		ones, _ := nw.Mask.Size()
+		if ones != n.Size || !subnetContains(vpc, nw) || subnetOverlaps(taken, nw) != nil {
+			continue
+		}
+
+		d.Networks[i].Subnet = nw.String()
+		taken = append(taken, nw)
+	}
+
+	for i, n := range d.Networks {
+		if n.Size < MINSUBNETSIZE || n.Size > MAXSUBNETSIZE || n.Subnet != "" {
+			continue
+		}
+
+		nw := freeSubnet(vpc, n.Size, taken)
+		if nw == nil {
+			continue
+		}
+
+		d.Networks[i].Subnet = nw.String()
+		taken = append(taken, nw)
+	}
+}
+
+// validateSubnets checks that every network is within the vpc subnet and
+// does not overlap with any other network
+func (d *Definition) validateSubnets() ValidationErrors {
+	var errs ValidationErrors
+	var checked []Network
+
+	_, vpc, vpcErr := net.ParseCIDR(d.VpcSubnet)
+
+	for _, n := range d.Networks {
+		if n.Size > 0 && n.Subnet == "" {
+			if vpcErr != nil {
+				errs.add("network", n.Name, fieldError("size", "Network size can only be used when a vpc_subnet is specified"))
+			} else if n.Size >= MINSUBNETSIZE && n.Size <= MAXSUBNETSIZE {
+				errs.add("network", n.Name, fieldErrorf("size", "Network could not be allocated a /%d subnet within the vpc subnet", n.Size))
+			}
+			continue
+		}
+
+		_, nw, err := net.ParseCIDR(n.Subnet)
+		if err != nil {
+			continue
+		}
+
+		if vpcErr == nil && !subnetContains(vpc, nw) {
+			errs.add("network", n.Name, fieldErrorf("subnet", "Network subnet (%s) is not within the vpc subnet (%s)", n.Subnet, d.VpcSubnet))
+		}
+
+		for _, c := range checked {
+			_, cnw, _ := net.ParseCIDR(c.Subnet)
+			if subnetOverlaps([]*net.IPNet{cnw}, nw) != nil {
+				errs.add("network", n.Name, fieldErrorf("subnet", "Network subnet (%s) overlaps with network (%s)", n.Subnet, c.Name))
+				break
+			}
+		}
+
+		checked = append(checked, n)
+	}
+
+	return errs
+}
+
+// freeSubnet returns the lowest block of a given size within the vpc range
+// that does not overlap with any taken subnet
+func freeSubnet(vpc *net.IPNet, size int, taken []*net.IPNet) *net.IPNet {
+	vones, bits := vpc.Mask.Size()
+	if size < vones || vpc.IP.To4() == nil {
+		return nil
+	}
+
+	block := uint64(1) << uint(bits-size)
+	start := uint64(ipToInt(vpc.IP))
+	end := start + (uint64(1) << uint(bits-vones))
+
+	for ip := start; ip+block <= end; {
+		nw := &net.IPNet{IP: intToIP(uint32(ip)), Mask: net.CIDRMask(size, bits)}
+
+		t := subnetOverlaps(taken, nw)
+		if t == nil {
+			return nw
+		}
+
+		// skip past the overlapping subnet, keeping the block aligned
+		tones, _ := t.Mask.Size()
+		next := uint64(ipToInt(t.IP)) + (uint64(1) << uint(bits-tones))
+		if next <= ip {
+			next = ip + block
+		}
+		ip = (next + block - 1) / block * block
+	}
+
+	return nil
+}
+
+// subnetContains returns true if a subnet is entirely within another
+func subnetContains(outer, inner *net.IPNet) bool {
+	oones, _ := outer.Mask.Size()
+	iones, _ := inner.Mask.Size()
+
+	return iones >= oones && outer.Contains(inner.IP)
+}
+
+// subnetOverlaps returns the first subnet that overlaps with the given subnet
+func subnetOverlaps(subnets []*net.IPNet, nw *net.IPNet) *net.IPNet {
+	for _, s := range subnets {
+		if s.Contains(nw.IP) || nw.Contains(s.IP) {
+			return s
+		}
+	}
+	return nil
+}
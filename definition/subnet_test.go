@@ -0,0 +1,116 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSubnetValidation(t *testing.T) {
+	Convey("Given a definition with networks", t, func() {
+		d := Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+			VpcSubnet:  "10.0.0.0/16",
+			Networks: []Network{
+				{Name: "web", Subnet: "10.0.0.0/24"},
+				{Name: "db", Subnet: "10.0.1.0/24"},
+			},
+		}
+
+		Convey("When the networks are valid", func() {
+			errs := d.validateSubnets()
+			Convey("Then it should not return an error", func() {
+				So(errs, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a network is outside of the vpc subnet", func() {
+			d.Networks[1].Subnet = "10.1.1.0/24"
+			errs := d.validateSubnets()
+			Convey("Then it should return an error", func() {
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0], ShouldResemble, ValidationError{Type: "network", Name: "db", Field: "subnet", Message: "Network subnet (10.1.1.0/24) is not within the vpc subnet (10.0.0.0/16)"})
+			})
+		})
+
+		Convey("When networks overlap", func() {
+			d.Networks[1].Subnet = "10.0.0.128/25"
+			errs := d.validateSubnets()
+			Convey("Then it should return an error", func() {
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0].Message, ShouldEqual, "Network subnet (10.0.0.128/25) overlaps with network (web)")
+			})
+		})
+
+		Convey("When a network has a size but no vpc subnet", func() {
+			d.VpcSubnet = ""
+			d.Networks[1].Subnet = ""
+			d.Networks[1].Size = 24
+			d.AllocateSubnets(nil)
+			errs := d.validateSubnets()
+			Convey("Then it should return an error", func() {
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0].Field, ShouldEqual, "size")
+				So(errs[0].Message, ShouldEqual, "Network size can only be used when a vpc_subnet is specified")
+			})
+		})
+	})
+}
+
+func TestAllocateSubnets(t *testing.T) {
+	Convey("Given a definition with sized networks", t, func() {
+		d := Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+			VpcSubnet:  "10.0.0.0/21",
+			Networks: []Network{
+				{Name: "web", Size: 24},
+				{Name: "db", Subnet: "10.0.0.0/24"},
+				{Name: "app", Size: 23},
+			},
+		}
+
+		Convey("When allocating subnets", func() {
+			d.AllocateSubnets(nil)
+			Convey("Then it should allocate free aligned blocks from the vpc", func() {
+				So(d.Networks[0].Subnet, ShouldEqual, "10.0.1.0/24")
+				So(d.Networks[1].Subnet, ShouldEqual, "10.0.0.0/24")
+				So(d.Networks[2].Subnet, ShouldEqual, "10.0.2.0/23")
+				So(d.validateSubnets(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When allocating subnets with a previous allocation", func() {
+			d.AllocateSubnets(map[string]string{"app": "10.0.4.0/23", "web": "10.0.3.0/24"})
+			Convey("Then it should keep previously allocated subnets", func() {
+				So(d.Networks[0].Subnet, ShouldEqual, "10.0.3.0/24")
+				So(d.Networks[2].Subnet, ShouldEqual, "10.0.4.0/23")
+			})
+		})
+
+		Convey("When allocating subnets with a previous allocation of a different size", func() {
+			d.Networks[2].Size = 24
+			d.AllocateSubnets(map[string]string{"app": "10.0.4.0/23", "web": "10.0.3.0/24"})
+			Convey("Then it should allocate a new subnet", func() {
+				So(d.Networks[0].Subnet, ShouldEqual, "10.0.3.0/24")
+				So(d.Networks[2].Subnet, ShouldEqual, "10.0.1.0/24")
+			})
+		})
+
+		Convey("When there is no room left in the vpc", func() {
+			d.Networks[2].Size = 21
+			d.AllocateSubnets(nil)
+			errs := d.validateSubnets()
+			Convey("Then validation should return an error", func() {
+				So(d.Networks[2].Subnet, ShouldEqual, "")
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0].Message, ShouldEqual, "Network could not be allocated a /21 subnet within the vpc subnet")
+			})
+		})
+	})
+}
@@ -10,6 +10,9 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"unicode"
+
+	"github.com/ernestio/aws-definition-mapper/secret"
 )
 
 const (
@@ -31,6 +34,21 @@ const (
 	AWSMAXNAME = 50
 )
 
+// countSubnets returns the number of subnets the named networks are expanded into
+func countSubnets(networks []Network, names []string) int {
+	var count int
+
+	for _, name := range names {
+		for _, n := range networks {
+			if n.Name == name {
+				count = count + len(n.Zones())
+			}
+		}
+	}
+
+	return count
+}
+
 func isNetwork(networks []Network, name string) bool {
 	for _, network := range networks {
 		if network.Name == name {
@@ -216,3 +234,45 @@ func appendUnique(items []string, item string) []string {
 	}
 	return append(items, item)
 }
+
+// validateSecret checks the syntax of a secret reference without resolving
+// it, as secrets are only resolved once a build is sent to the fsm. Values
+// that are not a secret reference are always valid
+func validateSecret(value string) error {
+	if !secret.IsReference(value) {
+		return nil
+	}
+
+	_, _, err := secret.Parse(value)
+
+	return err
+}
+
+// validateDatabasePassword checks the database password of an rds component.
+// The length and characters of a secret reference are only known once it
+// is resolved, so only its syntax is checked
+func validateDatabasePassword(component, password string) error {
+	if password == "" {
+		return fieldErrorf("database_password", "%s database password should not be null", component)
+	}
+
+	if err := validateSecret(password); err != nil {
+		return fieldErrorf("database_password", "%s database password secret reference is not valid: %s", component, err.Error())
+	}
+
+	if secret.IsReference(password) {
+		return nil
+	}
+
+	if len(password) < 8 || len(password) > 41 {
+		return fieldErrorf("database_password", "%s database password should be between 8 and 41 characters", component)
+	}
+
+	for _, c := range password {
+		if unicode.IsSymbol(c) || unicode.IsMark(c) {
+			return fieldErrorf("database_password", "%s database password contains an offending character: '%c'", component, c)
+		}
+	}
+
+	return nil
+}
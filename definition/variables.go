@@ -0,0 +1,151 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Variable : A definition variable that can be interpolated with ${name}
+type Variable struct {
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// variableRef matches a variable reference. A reference prefixed with an
+// extra $, i.e. $${name}, is escaped
+var variableRef = regexp.MustCompile(`(\$?)\$\{([a-zA-Z0-9_\-]+)\}`)
+
+// ResolveVariables interpolates the variables of a json definition, returning
+// the resolved definition. Variable defaults are taken from the definitions
+// variables block and can be overridden by the given values. Any reference
+// to an undeclared variable, or to a variable without a value, is returned
+// as a validation error. Other references, such as shell variables in user
+// data, must be escaped as $${name}
+func ResolveVariables(data []byte, overrides map[string]interface{}) ([]byte, error) {
+	var errs ValidationErrors
+	var service map[string]interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(&service); err != nil {
+		return nil, err
+	}
+
+	var declared map[string]Variable
+	if v, ok := service["variables"]; ok {
+		vd, _ := json.Marshal(v)
+		if err := json.Unmarshal(vd, &declared); err != nil {
+			errs.add("variable", "", fieldError("variables", "Variables should specify a default and description for each variable"))
+			return nil, errs
+		}
+	}
+
+	values := make(map[string]interface{})
+	for name, v := range declared {
+		if v.Default != nil {
+			values[name] = v.Default
+		}
+	}
+
+	for _, name := range sortedKeys(overrides) {
+		if _, ok := declared[name]; !ok {
+			errs.add("variable", name, fieldErrorf("variables", "Variable (%s) is not declared in the definition", name))
+			continue
+		}
+		values[name] = overrides[name]
+	}
+
+	for _, key := range sortedKeys(service) {
+		if key == "variables" {
+			continue
+		}
+		service[key] = resolveValue(service[key], key, declared, values, &errs)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return json.Marshal(service)
+}
+
+// resolveValue interpolates variables on a json value and all of its children
+func resolveValue(v interface{}, path string, declared map[string]Variable, values map[string]interface{}, errs *ValidationErrors) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(x) {
+			x[key] = resolveValue(x[key], path+"."+key, declared, values, errs)
+		}
+	case []interface{}:
+		for i := range x {
+			x[i] = resolveValue(x[i], fmt.Sprintf("%s[%d]", path, i), declared, values, errs)
+		}
+	case string:
+		return resolveString(x, path, declared, values, errs)
+	}
+
+	return v
+}
+
+// resolveString interpolates the variables of a string. A string that only
+// references a single variable is replaced by the variables value, keeping
+// its type, so that numbers and lists can be set from variables. Escaped
+// references are unescaped
+func resolveString(s, path string, declared map[string]Variable, values map[string]interface{}, errs *ValidationErrors) interface{} {
+	refs := variableRef.FindAllStringSubmatch(s, -1)
+	if len(refs) < 1 {
+		return s
+	}
+
+	for _, ref := range refs {
+		if ref[1] != "" {
+			continue
+		}
+		if _, ok := declared[ref[2]]; !ok {
+			errs.add("variable", ref[2], fieldErrorf(path, "Variable (%s) is not declared in the definition. Escape it as $${%s} if it should be kept", ref[2], ref[2]))
+			return s
+		}
+		if _, ok := values[ref[2]]; !ok {
+			errs.add("variable", ref[2], fieldErrorf(path, "Variable (%s) has no value. Specify a default or pass it in the payload variables", ref[2]))
+			return s
+		}
+	}
+
+	if len(refs) == 1 && refs[0][0] == s && refs[0][1] == "" {
+		return values[refs[0][2]]
+	}
+
+	return variableRef.ReplaceAllStringFunc(s, func(ref string) string {
+		m := variableRef.FindStringSubmatch(ref)
+		name := m[2]
+
+		if m[1] != "" {
+			return ref[1:]
+		}
+
+		switch value := values[name].(type) {
+		case map[string]interface{}, []interface{}:
+			errs.add("variable", name, fieldErrorf(path, "Variable (%s) can't be interpolated into a string", name))
+			return ref
+		default:
+			return fmt.Sprint(value)
+		}
+	})
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
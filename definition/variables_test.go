@@ -0,0 +1,114 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package definition
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResolveVariables(t *testing.T) {
+	Convey("Given a payload with a definition declaring variables", t, func() {
+		data := []byte(`{
+			"id": "test",
+			"service": {
+				"name": "${env}-service",
+				"vpc_subnet": "${vpc}",
+				"variables": {
+					"env": {"default": "staging"},
+					"vpc": {"default": "10.0.0.0/16"},
+					"count": {"default": 1},
+					"type": {}
+				},
+				"instances": [{"name": "web", "type": "${type}", "count": "${count}", "network": "web"}]
+			},
+			"variables": {"type": "m3.medium"}
+		}`)
+
+		Convey("When parsing the payload", func() {
+			p, err := PayloadFromJSON(data)
+			Convey("Then it should interpolate every variable", func() {
+				So(err, ShouldBeNil)
+				So(p.Service.Name, ShouldEqual, "staging-service")
+				So(p.Service.VpcSubnet, ShouldEqual, "10.0.0.0/16")
+				So(p.Service.Instances[0].Type, ShouldEqual, "m3.medium")
+				So(p.Service.Instances[0].Count, ShouldEqual, 1)
+				So(len(p.Service.Variables), ShouldEqual, 4)
+			})
+		})
+
+		Convey("When the payload overrides a default", func() {
+			data = []byte(`{"service": {"name": "svc-${env}", "variables": {"env": {"default": "staging"}}, "instances": [{"count": "${env}"}]}, "variables": {"env": 3}}`)
+			p, err := PayloadFromJSON(data)
+			Convey("Then it should use the payload value", func() {
+				So(err, ShouldBeNil)
+				So(p.Service.Name, ShouldEqual, "svc-3")
+				So(p.Service.Instances[0].Count, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When a variable has no value", func() {
+			data = []byte(`{"service": {"variables": {"type": {}}, "instances": [{"type": "${type}"}]}}`)
+			_, err := PayloadFromJSON(data)
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.(ValidationErrors)[0].Type, ShouldEqual, "variable")
+				So(err.(ValidationErrors)[0].Name, ShouldEqual, "type")
+				So(err.(ValidationErrors)[0].Field, ShouldEqual, "instances[0].type")
+				So(err.Error(), ShouldEqual, "Variable (type) has no value. Specify a default or pass it in the payload variables")
+			})
+		})
+
+		Convey("When a variable is not declared", func() {
+			data = []byte(`{"service": {"name": "test", "instances": [{"user_data": "echo ${HOME}"}]}}`)
+			_, err := PayloadFromJSON(data)
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.(ValidationErrors)[0].Type, ShouldEqual, "variable")
+				So(err.(ValidationErrors)[0].Name, ShouldEqual, "HOME")
+				So(err.(ValidationErrors)[0].Field, ShouldEqual, "instances[0].user_data")
+				So(err.Error(), ShouldEqual, "Variable (HOME) is not declared in the definition. Escape it as $${HOME} if it should be kept")
+			})
+		})
+
+		Convey("When instance user data escapes shell variables", func() {
+			data = []byte(`{"service": {"variables": {"env": {"default": "prod"}}, "instances": [{"user_data": "echo $${HOME} $${env} ${env}"}]}}`)
+			p, err := PayloadFromJSON(data)
+			Convey("Then only unescaped variables should be interpolated", func() {
+				So(err, ShouldBeNil)
+				So(p.Service.Instances[0].UserData, ShouldEqual, "echo ${HOME} ${env} prod")
+			})
+		})
+
+		Convey("When a value only holds an escaped variable", func() {
+			data = []byte(`{"service": {"name": "$${env}", "variables": {"env": {"default": "prod"}, "type": {}}, "instances": [{"type": "$${type}"}]}}`)
+			p, err := PayloadFromJSON(data)
+			Convey("Then it should be unescaped rather than interpolated", func() {
+				So(err, ShouldBeNil)
+				So(p.Service.Name, ShouldEqual, "${env}")
+				So(p.Service.Instances[0].Type, ShouldEqual, "${type}")
+			})
+		})
+
+		Convey("When the payload overrides a variable that is not declared", func() {
+			data = []byte(`{"service": {"name": "test"}, "variables": {"env": "prod"}}`)
+			_, err := PayloadFromJSON(data)
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Variable (env) is not declared in the definition")
+			})
+		})
+
+		Convey("When a list variable is interpolated into a string", func() {
+			data = []byte(`{"service": {"name": "svc-${nws}", "variables": {"nws": {"default": ["a", "b"]}}}}`)
+			_, err := PayloadFromJSON(data)
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "Variable (nws) can't be interpolated into a string")
+			})
+		})
+	})
+}
@@ -6,7 +6,6 @@ package main
 
 import (
 	"encoding/json"
-	"fmt"
 	"log"
 	"os"
 	"runtime"
@@ -35,6 +34,15 @@ func main() {
 	if _, err := nc.Subscribe("definition.map.import.aws", importDefinitionHandler); err != nil {
 		log.Println(err)
 	}
+	if _, err := nc.Subscribe("definition.map.plan.aws", planDefinitionHandler); err != nil {
+		log.Println(err)
+	}
+	if _, err := nc.Subscribe("definition.map.drift.aws", driftDefinitionHandler); err != nil {
+		log.Println(err)
+	}
+	if _, err := nc.Subscribe("definition.map.rollback.aws", rollbackDefinitionHandler); err != nil {
+		log.Println(err)
+	}
 
 	if _, err := nc.Subscribe("service.import.aws.done", importDoneHandler); err != nil {
 		log.Println(err)
@@ -48,65 +56,72 @@ func createDefinitionHandler(msg *nats.Msg) {
 
 	p, err := definition.PayloadFromJSON(msg.Data)
 	if err != nil {
-		log.Println("ERROR: failed to parse payload")
-		if err := nc.Publish(msg.Reply, []byte(`{"error":"Failed to parse payload."}`)); err != nil {
-			log.Println(err)
+		publishError(msg.Reply, payloadError(err))
+		return
+	}
+
+	// previous output message if it exists
+	if p.PrevID != "" {
+		om, err = getPreviousServiceMapping(p.PrevID)
+		if err != nil {
+			publishError(msg.Reply, output.NewError(output.ERRPREVIOUSMAPPINGUNAVAILABLE, "Failed to get previous output.", nil))
+			return
 		}
+	}
+
+	m, e := mapper.MapCreation(p, &om)
+	if e != nil {
+		publishError(msg.Reply, e)
 		return
 	}
 
-	err = p.Service.Validate()
+	// Only secret references are sent to the fsm, so the mapping it stores
+	// never holds a secret. Check they can be resolved when it needs them
+	err = m.CheckSecrets()
 	if err != nil {
-		log.Println("ERROR: " + err.Error())
-		if err := nc.Publish(msg.Reply, []byte(`{"error":"`+err.Error()+`"}`)); err != nil {
-			log.Println(err)
-		}
+		publishError(msg.Reply, output.NewError(output.ERRSECRETUNRESOLVED, "Could not resolve secret "+err.Error(), nil))
 		return
 	}
 
-	// new fsm message
-	m := mapper.ConvertPayload(p)
+	data, err := json.Marshal(m)
+	if err != nil {
+		publishError(msg.Reply, output.NewError(output.ERRMARSHALFAILED, "Failed marshal output message.", nil))
+		return
+	}
+
+	if err := nc.Publish(msg.Reply, data); err != nil {
+		log.Println(err)
+	}
+}
+
+func planDefinitionHandler(msg *nats.Msg) {
+	var om output.FSMMessage
+
+	p, err := definition.PayloadFromJSON(msg.Data)
+	if err != nil {
+		publishError(msg.Reply, payloadError(err))
+		return
+	}
 
 	// previous output message if it exists
 	if p.PrevID != "" {
 		om, err = getPreviousServiceMapping(p.PrevID)
 		if err != nil {
-			log.Println("ERROR: failed to get previous output")
-			if err := nc.Publish(msg.Reply, []byte(`{"error":"Failed to get previous output."}`)); err != nil {
-				log.Println(err)
-			}
-			return
-		}
-
-		if p.Service.VpcID != "" && p.Service.VpcID != om.VPCs.Items[0].VpcID {
-			log.Println("ERROR: VPC ID cannot change between builds.")
-			if err := nc.Publish(msg.Reply, []byte(`{"error":"VPC ID cannot change between builds."}`)); err != nil {
-				log.Println(err)
-			}
+			publishError(msg.Reply, output.NewError(output.ERRPREVIOUSMAPPINGUNAVAILABLE, "Failed to get previous output.", nil))
 			return
 		}
 	}
 
-	// Map provider data from previous build
-	mapper.MapProviderData(m, &om)
-
-	// Check for changes and create workflow arcs
-	m.Diff(om)
-
-	err = m.GenerateWorkflow("create-workflow.json")
-	if err != nil {
-		log.Println(err.Error())
-		if err := nc.Publish(msg.Reply, []byte(`{"error":"Could not generate workflow."}`)); err != nil {
-			log.Println(err)
-		}
+	// Check for changes, but don't generate a workflow
+	m, e := mapper.MapChanges(p, &om)
+	if e != nil {
+		publishError(msg.Reply, e)
 		return
 	}
 
-	data, err := json.Marshal(m)
+	data, err := json.Marshal(m.Plan())
 	if err != nil {
-		if err := nc.Publish(msg.Reply, []byte(`{"error":"Failed marshal output message."}`)); err != nil {
-			log.Println(err)
-		}
+		publishError(msg.Reply, output.NewError(output.ERRMARSHALFAILED, "Failed marshal plan.", nil))
 		return
 	}
 
@@ -118,75 +133,91 @@ func createDefinitionHandler(msg *nats.Msg) {
 func deleteDefinitionHandler(msg *nats.Msg) {
 	p, err := definition.PayloadFromJSON(msg.Data)
 	if err != nil {
-		if err := nc.Publish(msg.Reply, []byte(`{"error":"Failed to parse payload."}`)); err != nil {
-			log.Println(err)
-		}
+		publishError(msg.Reply, payloadError(err))
 		return
 	}
 
 	m, err := getPreviousServiceMapping(p.PrevID)
 	if err != nil {
-		if err := nc.Publish(msg.Reply, []byte(`{"error":"Failed to get previous output."}`)); err != nil {
-			log.Println(err)
-		}
+		publishError(msg.Reply, output.NewError(output.ERRPREVIOUSMAPPINGUNAVAILABLE, "Failed to get previous output.", nil))
 		return
 	}
 
-	// Assign all items to delete
-	m.NetworksToDelete = m.Networks
-	for i := range m.NetworksToDelete.Items {
-		m.NetworksToDelete.Items[i].Status = ""
-	}
-	m.InstancesToDelete = m.Instances
-	for i := range m.InstancesToDelete.Items {
-		m.InstancesToDelete.Items[i].Status = ""
+	// stored mappings don't hold credentials, so use the requested datacenter
+	if p.Datacenter.Name != "" {
+		m.Datacenters.Items = mapper.MapDatacenters(p.Datacenter)
 	}
-	m.FirewallsToDelete = m.Firewalls
-	for i := range m.FirewallsToDelete.Items {
-		m.FirewallsToDelete.Items[i].Status = ""
+
+	m.DeleteAll()
+
+	// Generate delete workflow
+	if err := m.GenerateWorkflow("delete"); err != nil {
+		log.Println(err)
+		publishError(msg.Reply, output.NewError(output.ERRWORKFLOWGENERATIONFAILED, "Could not generate workflow.", nil))
+		return
 	}
-	m.NatsToDelete = m.Nats
-	for i := range m.NatsToDelete.Items {
-		m.NatsToDelete.Items[i].Status = ""
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		publishError(msg.Reply, output.NewError(output.ERRMARSHALFAILED, "Failed marshal output message.", nil))
+		return
 	}
-	m.ELBsToDelete = m.ELBs
-	for i := range m.ELBsToDelete.Items {
-		m.ELBsToDelete.Items[i].Status = ""
+
+	if err := nc.Publish(msg.Reply, data); err != nil {
+		log.Println(err)
 	}
-	m.S3sToDelete = m.S3s
-	for i := range m.S3sToDelete.Items {
-		m.S3sToDelete.Items[i].Status = ""
+}
+
+func rollbackDefinitionHandler(msg *nats.Msg) {
+	var om output.FSMMessage
+
+	p, err := definition.PayloadFromJSON(msg.Data)
+	if err != nil {
+		publishError(msg.Reply, payloadError(err))
+		return
 	}
-	m.Route53sToDelete = m.Route53s
-	for i := range m.Route53sToDelete.Items {
-		m.Route53sToDelete.Items[i].Status = ""
+
+	// mapping of the failed build, holding the status of every component
+	m, err := getPreviousServiceMapping(p.ServiceID)
+	if err != nil {
+		publishError(msg.Reply, output.NewError(output.ERRPREVIOUSMAPPINGUNAVAILABLE, "Failed to get failed build output.", nil))
+		return
 	}
 
-	m.RDSClustersToDelete = m.RDSClusters
-	for i := range m.RDSClustersToDelete.Items {
-		m.RDSClustersToDelete.Items[i].Status = ""
+	// previous output message if it exists
+	if p.PrevID != "" {
+		om, err = getPreviousServiceMapping(p.PrevID)
+		if err != nil {
+			publishError(msg.Reply, output.NewError(output.ERRPREVIOUSMAPPINGUNAVAILABLE, "Failed to get previous output.", nil))
+			return
+		}
 	}
 
-	m.RDSInstancesToDelete = m.RDSInstances
-	for i := range m.RDSInstancesToDelete.Items {
-		m.RDSInstancesToDelete.Items[i].Status = ""
+	r := mapper.Rollback(&m, &om)
+
+	// stored mappings don't hold credentials, so use the requested datacenter
+	if p.Datacenter.Name != "" {
+		r.Datacenters.Items = mapper.MapDatacenters(p.Datacenter)
 	}
 
-	m.EBSVolumesToDelete = m.EBSVolumes
-	for i := range m.EBSVolumesToDelete.Items {
-		m.EBSVolumesToDelete.Items[i].Status = ""
+	err = r.GenerateWorkflow("create")
+	if err != nil {
+		log.Println(err.Error())
+		publishError(msg.Reply, output.NewError(output.ERRWORKFLOWGENERATIONFAILED, "Could not generate workflow.", nil))
+		return
 	}
 
-	// Generate delete workflow
-	if err := m.GenerateWorkflow("delete-workflow.json"); err != nil {
-		log.Println(err)
+	// Only secret references are sent to the fsm, so the mapping it stores
+	// never holds a secret. Check they can be resolved when it needs them
+	err = r.CheckSecrets()
+	if err != nil {
+		publishError(msg.Reply, output.NewError(output.ERRSECRETUNRESOLVED, "Could not resolve secret "+err.Error(), nil))
+		return
 	}
 
-	data, err := json.Marshal(m)
+	data, err := json.Marshal(r)
 	if err != nil {
-		if err := nc.Publish(msg.Reply, []byte(`{"error":"Failed marshal output message."}`)); err != nil {
-			log.Println(err)
-		}
+		publishError(msg.Reply, output.NewError(output.ERRMARSHALFAILED, "Failed marshal output message.", nil))
 		return
 	}
 
@@ -200,10 +231,7 @@ func importDefinitionHandler(msg *nats.Msg) {
 
 	p, err := definition.PayloadFromJSON(msg.Data)
 	if err != nil {
-		log.Println("ERROR: failed to parse payload")
-		if err := nc.Publish(msg.Reply, []byte(`{"error":"Failed to parse payload."}`)); err != nil {
-			log.Println(err)
-		}
+		publishError(msg.Reply, payloadError(err))
 		return
 	}
 
@@ -214,30 +242,23 @@ func importDefinitionHandler(msg *nats.Msg) {
 	if p.PrevID != "" {
 		om, err = getPreviousServiceMapping(p.PrevID)
 		if err != nil {
-			log.Println("ERROR: failed to get previous output")
-			if err := nc.Publish(msg.Reply, []byte(`{"error":"Failed to get previous output."}`)); err != nil {
-				log.Println(err)
-			}
+			publishError(msg.Reply, output.NewError(output.ERRPREVIOUSMAPPINGUNAVAILABLE, "Failed to get previous output.", nil))
 			return
 		}
 	}
 
 	m.VPCs.Items = []output.VPC{}
 
-	err = m.GenerateWorkflow("import-workflow.json")
+	err = m.GenerateWorkflow("import")
 	if err != nil {
 		log.Println(err.Error())
-		if err := nc.Publish(msg.Reply, []byte(`{"error":"Could not generate workflow."}`)); err != nil {
-			log.Println(err)
-		}
+		publishError(msg.Reply, output.NewError(output.ERRWORKFLOWGENERATIONFAILED, "Could not generate workflow.", nil))
 		return
 	}
 
 	data, err := json.Marshal(m)
 	if err != nil {
-		if err := nc.Publish(msg.Reply, []byte(`{"error":"Failed marshal output message."}`)); err != nil {
-			log.Println(err)
-		}
+		publishError(msg.Reply, output.NewError(output.ERRMARSHALFAILED, "Failed marshal output message.", nil))
 		return
 	}
 
@@ -252,6 +273,35 @@ func importDefinitionHandler(msg *nats.Msg) {
 	}
 }
 
+func driftDefinitionHandler(msg *nats.Msg) {
+	var m output.FSMMessage
+
+	if err := json.Unmarshal(msg.Data, &m); err != nil {
+		publishError(msg.Reply, output.NewError(output.ERRINVALIDPAYLOAD, "Failed to parse payload.", nil))
+		return
+	}
+
+	om, err := getPreviousServiceMapping(m.ID)
+	if err != nil {
+		publishError(msg.Reply, output.NewError(output.ERRPREVIOUSMAPPINGUNAVAILABLE, "Failed to get previous output.", nil))
+		return
+	}
+
+	// Set missing values on the imported fsm message
+	mapper.UpdateFSMMessageValues(&m)
+
+	// Compare what has been imported with what ernest last applied
+	data, err := json.Marshal(m.Drift(om))
+	if err != nil {
+		publishError(msg.Reply, output.NewError(output.ERRMARSHALFAILED, "Failed marshal drift.", nil))
+		return
+	}
+
+	if err := nc.Publish(msg.Reply, data); err != nil {
+		log.Println(err)
+	}
+}
+
 func importDoneHandler(msg *nats.Msg) {
 	var m output.FSMMessage
 
@@ -268,8 +318,12 @@ func importDoneHandler(msg *nats.Msg) {
 	// Set missing values on fsm message
 	mapper.UpdateFSMMessageValues(&m)
 
-	// convert the payload to a definition
-	d := mapper.ConvertFSMMessage(&m)
+	// convert the payload to a definition, never storing any secrets
+	d, err := mapper.ConvertFSMMessage(&m).Redacted()
+	if err != nil {
+		log.Println(err)
+		return
+	}
 
 	dj, err := json.Marshal(d)
 	if err != nil {
@@ -277,8 +331,6 @@ func importDoneHandler(msg *nats.Msg) {
 		return
 	}
 
-	fmt.Println(string(dj))
-
 	dy, err := yaml.JSONToYAML(dj)
 	if err != nil {
 		log.Println(err)
@@ -302,7 +354,13 @@ func importDoneHandler(msg *nats.Msg) {
 		return
 	}
 
-	mapping, err := json.Marshal(m)
+	rm, err := m.Redacted()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	mapping, err := json.Marshal(rm)
 	if err != nil {
 		log.Println(err)
 		return
@@ -330,6 +388,23 @@ func importDoneHandler(msg *nats.Msg) {
 	}
 }
 
+// payloadError builds an error response for a payload that could not be
+// parsed, listing any variables of the definition that could not be resolved
+func payloadError(err error) *output.Error {
+	if _, ok := err.(definition.ValidationErrors); ok {
+		return mapper.ValidationError(err)
+	}
+	return output.NewError(output.ERRINVALIDPAYLOAD, "Failed to parse payload.", nil)
+}
+
+// publishError logs and replies to a request with an error response
+func publishError(reply string, e *output.Error) {
+	log.Println("ERROR: " + e.Message)
+	if err := nc.Publish(reply, e.JSON()); err != nil {
+		log.Println(err)
+	}
+}
+
 func getPreviousServiceMapping(id string) (output.FSMMessage, error) {
 	var payload output.FSMMessage
 
@@ -343,6 +418,9 @@ func getPreviousServiceMapping(id string) (output.FSMMessage, error) {
 		return payload, err
 	}
 
+	// compare secrets by their reference rather than their value
+	payload.RedactSecrets()
+
 	return payload, nil
 }
 
@@ -0,0 +1,190 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapper
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ernestio/aws-definition-mapper/definition"
+	"github.com/ernestio/aws-definition-mapper/output"
+)
+
+// MapALBs : Maps the application loadbalancers from a given input payload.
+func MapALBs(d definition.Definition) []output.ALB {
+	var albs []output.ALB
+
+	for _, alb := range d.ALBs {
+		name := d.GeneratedName() + alb.Name
+		var sgroups []string
+
+		for _, sg := range alb.SecurityGroups {
+			sgroups = append(sgroups, d.GeneratedName()+sg)
+		}
+
+		a := output.ALB{
+			Name:             name,
+			IsPrivate:        alb.Private,
+			SecurityGroups:   sgroups,
+			Tags:             mapTagsServiceOnly(d.Name),
+			DatacenterType:   "$(datacenters.items.0.type)",
+			DatacenterName:   "$(datacenters.items.0.name)",
+			AccessKeyID:      "$(datacenters.items.0.aws_access_key_id)",
+			SecretAccessKey:  "$(datacenters.items.0.aws_secret_access_key)",
+			DatacenterRegion: "$(datacenters.items.0.region)",
+			Type:             "$(datacenters.items.0.type)",
+			VpcID:            "$(vpcs.items.0.vpc_id)",
+		}
+
+		for _, tg := range alb.TargetGroups {
+			t := output.ALBTargetGroup{
+				Name:      name + "-" + tg.Name,
+				Port:      tg.Port,
+				Protocol:  strings.ToUpper(tg.Protocol),
+				Instances: tg.Instances,
+				HealthCheck: output.ALBHealthCheck{
+					Path:               tg.HealthCheck.Path,
+					Port:               tg.HealthCheck.Port,
+					Protocol:           strings.ToUpper(tg.HealthCheck.Protocol),
+					Interval:           tg.HealthCheck.Interval,
+					Timeout:            tg.HealthCheck.Timeout,
+					HealthyThreshold:   tg.HealthCheck.HealthyThreshold,
+					UnhealthyThreshold: tg.HealthCheck.UnhealthyThreshold,
+					Matcher:            tg.HealthCheck.Matcher,
+				},
+			}
+
+			for _, instance := range tg.Instances {
+				i := d.FindInstance(instance)
+				if i != nil {
+					for x := 0; x < i.Count; x++ {
+						iname := d.GeneratedName() + i.Name + "-" + strconv.Itoa(x+1)
+						t.InstanceAWSIDs = append(t.InstanceAWSIDs, `$(instances.items.#[name="`+iname+`"].instance_aws_id)`)
+						t.InstanceNames = append(t.InstanceNames, iname)
+					}
+				}
+			}
+
+			a.TargetGroups = append(a.TargetGroups, t)
+		}
+
+		for _, listener := range alb.Listeners {
+			l := output.ALBListener{
+				Port:               listener.Port,
+				Protocol:           strings.ToUpper(listener.Protocol),
+				SSLCert:            listener.SSLCert,
+				DefaultTargetGroup: name + "-" + listener.DefaultTargetGroup,
+			}
+
+			for _, rule := range listener.Rules {
+				l.Rules = append(l.Rules, output.ALBListenerRule{
+					Path:        rule.Path,
+					Host:        rule.Host,
+					Priority:    rule.Priority,
+					TargetGroup: name + "-" + rule.TargetGroup,
+				})
+			}
+
+			a.Listeners = append(a.Listeners, l)
+		}
+
+		for _, subnet := range mapSubnetNames(d, alb.Subnets) {
+			a.NetworkAWSIDs = append(a.NetworkAWSIDs, `$(networks.items.#[name="`+subnet+`"].network_aws_id)`)
+		}
+
+		for _, sg := range a.SecurityGroups {
+			a.SecurityGroupAWSIDs = append(a.SecurityGroupAWSIDs, `$(firewalls.items.#[name="`+sg+`"].security_group_aws_id)`)
+		}
+
+		albs = append(albs, a)
+	}
+
+	return albs
+}
+
+// UpdateALBValues corrects missing values after an import
+func UpdateALBValues(m *output.FSMMessage) {
+	for i := 0; i < len(m.ALBs.Items); i++ {
+		m.ALBs.Items[i].Type = "$(datacenters.items.0.type)"
+		m.ALBs.Items[i].DatacenterName = "$(datacenters.items.0.name)"
+		m.ALBs.Items[i].DatacenterType = "$(datacenters.items.0.type)"
+		m.ALBs.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+		m.ALBs.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+		m.ALBs.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
+		m.ALBs.Items[i].VpcID = "$(vpcs.items.0.vpc_id)"
+		m.ALBs.Items[i].SecurityGroups = ComponentNamesFromIDs(m.Firewalls.Items, m.ALBs.Items[i].SecurityGroupAWSIDs)
+
+		for x, tg := range m.ALBs.Items[i].TargetGroups {
+			m.ALBs.Items[i].TargetGroups[x].Instances = ComponentGroupsFromIDs(m.Instances.Items, "ernest.instance_group", tg.InstanceAWSIDs)
+			m.ALBs.Items[i].TargetGroups[x].InstanceNames = ComponentNamesFromIDs(m.Instances.Items, tg.InstanceAWSIDs)
+		}
+	}
+}
+
+// MapDefinitionALBs : Maps output application loadbalancers into a definition defined application loadbalancers
+func MapDefinitionALBs(m *output.FSMMessage) []definition.ALB {
+	var albs []definition.ALB
+
+	prefix := m.Datacenters.Items[0].Name + "-" + m.ServiceName + "-"
+
+	for _, alb := range m.ALBs.Items {
+		sgroups := ComponentNamesFromIDs(m.Firewalls.Items, alb.SecurityGroupAWSIDs)
+
+		// target groups are named after their alb
+		tgprefix := alb.Name + "-"
+
+		a := definition.ALB{
+			Name:           ShortName(alb.Name, prefix),
+			Private:        alb.IsPrivate,
+			Subnets:        mapDefinitionNetworkNames(m, alb.NetworkAWSIDs, prefix),
+			SecurityGroups: ShortNames(sgroups, prefix),
+		}
+
+		for _, tg := range alb.TargetGroups {
+			instances := ComponentsByIDs(m.Instances.Items, tg.InstanceAWSIDs)
+
+			a.TargetGroups = append(a.TargetGroups, definition.ALBTargetGroup{
+				Name:      ShortName(tg.Name, tgprefix),
+				Port:      tg.Port,
+				Protocol:  strings.ToLower(tg.Protocol),
+				Instances: ComponentGroupsFromIDs(instances, "ernest.instance_group", tg.InstanceAWSIDs),
+				HealthCheck: definition.ALBHealthCheck{
+					Path:               tg.HealthCheck.Path,
+					Port:               tg.HealthCheck.Port,
+					Protocol:           strings.ToLower(tg.HealthCheck.Protocol),
+					Interval:           tg.HealthCheck.Interval,
+					Timeout:            tg.HealthCheck.Timeout,
+					HealthyThreshold:   tg.HealthCheck.HealthyThreshold,
+					UnhealthyThreshold: tg.HealthCheck.UnhealthyThreshold,
+					Matcher:            tg.HealthCheck.Matcher,
+				},
+			})
+		}
+
+		for _, listener := range alb.Listeners {
+			l := definition.ALBListener{
+				Port:               listener.Port,
+				Protocol:           strings.ToLower(listener.Protocol),
+				SSLCert:            listener.SSLCert,
+				DefaultTargetGroup: ShortName(listener.DefaultTargetGroup, tgprefix),
+			}
+
+			for _, rule := range listener.Rules {
+				l.Rules = append(l.Rules, definition.ALBListenerRule{
+					Path:        rule.Path,
+					Host:        rule.Host,
+					Priority:    rule.Priority,
+					TargetGroup: ShortName(rule.TargetGroup, tgprefix),
+				})
+			}
+
+			a.Listeners = append(a.Listeners, l)
+		}
+
+		albs = append(albs, a)
+	}
+
+	return albs
+}
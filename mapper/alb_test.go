@@ -0,0 +1,153 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapper
+
+import (
+	"testing"
+
+	"github.com/ernestio/aws-definition-mapper/definition"
+	"github.com/ernestio/aws-definition-mapper/output"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMapALBs(t *testing.T) {
+
+	Convey("Given a valid input definition", t, func() {
+		d := definition.Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+		}
+
+		d.Instances = append(d.Instances, definition.Instance{
+			Name:    "web",
+			Network: "web-nw",
+			Count:   2,
+		})
+
+		a := definition.ALB{
+			Name:           "test",
+			Subnets:        []string{"web-a", "web-b"},
+			SecurityGroups: []string{"web-sg"},
+			TargetGroups: []definition.ALBTargetGroup{
+				definition.ALBTargetGroup{
+					Name:        "web",
+					Port:        80,
+					Protocol:    "http",
+					Instances:   []string{"web"},
+					HealthCheck: definition.ALBHealthCheck{Path: "/health", Protocol: "http"},
+				},
+			},
+			Listeners: []definition.ALBListener{
+				definition.ALBListener{
+					Port:               443,
+					Protocol:           "https",
+					SSLCert:            "cert",
+					DefaultTargetGroup: "web",
+					Rules: []definition.ALBListenerRule{
+						definition.ALBListenerRule{Path: "/api/*", Priority: 1, TargetGroup: "web"},
+					},
+				},
+			},
+		}
+
+		d.ALBs = append(d.ALBs, a)
+
+		Convey("When i try to map albs", func() {
+
+			a := MapALBs(d)
+			Convey("Then it should map input albs", func() {
+				So(len(a), ShouldEqual, 1)
+				So(a[0].Name, ShouldEqual, "datacenter-service-test")
+				So(len(a[0].NetworkAWSIDs), ShouldEqual, 2)
+				So(a[0].NetworkAWSIDs[0], ShouldEqual, `$(networks.items.#[name="datacenter-service-web-a"].network_aws_id)`)
+				So(len(a[0].SecurityGroupAWSIDs), ShouldEqual, 1)
+				So(a[0].SecurityGroupAWSIDs[0], ShouldEqual, `$(firewalls.items.#[name="datacenter-service-web-sg"].security_group_aws_id)`)
+				So(len(a[0].TargetGroups), ShouldEqual, 1)
+				So(a[0].TargetGroups[0].Name, ShouldEqual, "datacenter-service-test-web")
+				So(a[0].TargetGroups[0].Protocol, ShouldEqual, "HTTP")
+				So(a[0].TargetGroups[0].HealthCheck.Path, ShouldEqual, "/health")
+				So(len(a[0].TargetGroups[0].InstanceAWSIDs), ShouldEqual, 2)
+				So(a[0].TargetGroups[0].InstanceAWSIDs[1], ShouldEqual, `$(instances.items.#[name="datacenter-service-web-2"].instance_aws_id)`)
+				So(len(a[0].Listeners), ShouldEqual, 1)
+				So(a[0].Listeners[0].Protocol, ShouldEqual, "HTTPS")
+				So(a[0].Listeners[0].DefaultTargetGroup, ShouldEqual, "datacenter-service-test-web")
+				So(a[0].Listeners[0].Rules[0].Path, ShouldEqual, "/api/*")
+				So(a[0].Listeners[0].Rules[0].TargetGroup, ShouldEqual, "datacenter-service-test-web")
+				So(a[0].Tags["ernest.service"], ShouldEqual, "service")
+			})
+
+		})
+	})
+
+	Convey("Given a valid output message", t, func() {
+		m := output.FSMMessage{
+			ServiceName: "service",
+		}
+
+		m.Datacenters.Items = append(m.Datacenters.Items, output.Datacenter{
+			Name: "datacenter",
+		})
+
+		m.Networks.Items = append(m.Networks.Items, output.Network{
+			NetworkAWSID: "n-0000000",
+			Name:         "datacenter-service-web",
+			Subnet:       "10.64.0.0/24",
+		})
+
+		m.Instances.Items = append(m.Instances.Items, output.Instance{
+			InstanceAWSID: "i-0000000",
+			Name:          "datacenter-service-web-1",
+			Tags:          map[string]string{"ernest.instance_group": "web"},
+		})
+
+		m.Firewalls.Items = append(m.Firewalls.Items, output.Firewall{
+			SecurityGroupAWSID: "sg-0000000",
+			Name:               "datacenter-service-web-sg",
+		})
+
+		m.ALBs.Items = append(m.ALBs.Items, output.ALB{
+			Name:                "datacenter-service-test",
+			NetworkAWSIDs:       []string{"n-0000000"},
+			SecurityGroupAWSIDs: []string{"sg-0000000"},
+			TargetGroups: []output.ALBTargetGroup{
+				output.ALBTargetGroup{
+					Name:           "datacenter-service-test-web",
+					Port:           80,
+					Protocol:       "HTTP",
+					InstanceAWSIDs: []string{"i-0000000"},
+				},
+			},
+			Listeners: []output.ALBListener{
+				output.ALBListener{
+					Port:               80,
+					Protocol:           "HTTP",
+					DefaultTargetGroup: "datacenter-service-test-web",
+					Rules: []output.ALBListenerRule{
+						output.ALBListenerRule{Host: "example.com", Priority: 1, TargetGroup: "datacenter-service-test-web"},
+					},
+				},
+			},
+		})
+
+		Convey("When i try to map albs", func() {
+
+			a := MapDefinitionALBs(&m)
+			Convey("Then it should return a correctly formed set of input albs", func() {
+				So(len(a), ShouldEqual, 1)
+				So(a[0].Name, ShouldEqual, "test")
+				So(a[0].Subnets, ShouldResemble, []string{"web"})
+				So(a[0].SecurityGroups, ShouldResemble, []string{"web-sg"})
+				So(len(a[0].TargetGroups), ShouldEqual, 1)
+				So(a[0].TargetGroups[0].Name, ShouldEqual, "web")
+				So(a[0].TargetGroups[0].Protocol, ShouldEqual, "http")
+				So(a[0].TargetGroups[0].Instances, ShouldResemble, []string{"web"})
+				So(a[0].Listeners[0].DefaultTargetGroup, ShouldEqual, "web")
+				So(a[0].Listeners[0].Rules[0].TargetGroup, ShouldEqual, "web")
+			})
+
+		})
+	})
+
+}
@@ -0,0 +1,105 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapper
+
+import (
+	"github.com/ernestio/aws-definition-mapper/definition"
+	"github.com/ernestio/aws-definition-mapper/output"
+)
+
+// MapAutoscalingGroups : Maps the autoscaling groups from a given input payload.
+func MapAutoscalingGroups(d definition.Definition) []output.AutoscalingGroup {
+	var asgs []output.AutoscalingGroup
+
+	for _, asg := range d.AutoscalingGroups {
+		name := d.GeneratedName() + asg.Name
+
+		var sgroups []string
+		for _, sg := range asg.LaunchConfiguration.SecurityGroups {
+			sgroups = append(sgroups, d.GeneratedName()+sg)
+		}
+
+		a := output.AutoscalingGroup{
+			Name: name,
+			LaunchConfiguration: output.LaunchConfiguration{
+				Image:               asg.LaunchConfiguration.Image,
+				Type:                asg.LaunchConfiguration.Type,
+				KeyPair:             asg.LaunchConfiguration.KeyPair,
+				UserData:            asg.LaunchConfiguration.UserData,
+				SecurityGroups:      sgroups,
+				SecurityGroupAWSIDs: mapInstanceSecurityGroupIDs(sgroups),
+			},
+			MinSize:          asg.MinSize,
+			MaxSize:          asg.MaxSize,
+			DesiredCapacity:  asg.DesiredCapacity,
+			Tags:             mapTags(name, d.Name),
+			ProviderType:     "$(datacenters.items.0.type)",
+			DatacenterType:   "$(datacenters.items.0.type)",
+			DatacenterName:   "$(datacenters.items.0.name)",
+			AccessKeyID:      "$(datacenters.items.0.aws_access_key_id)",
+			SecretAccessKey:  "$(datacenters.items.0.aws_secret_access_key)",
+			DatacenterRegion: "$(datacenters.items.0.region)",
+			VpcID:            "$(vpcs.items.0.vpc_id)",
+		}
+
+		for _, nw := range mapSubnetNames(d, asg.Networks) {
+			a.Networks = append(a.Networks, nw)
+			a.NetworkAWSIDs = append(a.NetworkAWSIDs, `$(networks.items.#[name="`+nw+`"].network_aws_id)`)
+		}
+
+		for _, elb := range asg.ELBs {
+			a.ELBs = append(a.ELBs, d.GeneratedName()+elb)
+		}
+
+		asgs = append(asgs, a)
+	}
+
+	return asgs
+}
+
+// UpdateAutoscalingGroupValues corrects missing values after an import
+func UpdateAutoscalingGroupValues(m *output.FSMMessage) {
+	for i := 0; i < len(m.AutoscalingGroups.Items); i++ {
+		m.AutoscalingGroups.Items[i].ProviderType = "$(datacenters.items.0.type)"
+		m.AutoscalingGroups.Items[i].DatacenterName = "$(datacenters.items.0.name)"
+		m.AutoscalingGroups.Items[i].DatacenterType = "$(datacenters.items.0.type)"
+		m.AutoscalingGroups.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+		m.AutoscalingGroups.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+		m.AutoscalingGroups.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
+		m.AutoscalingGroups.Items[i].VpcID = "$(vpcs.items.0.vpc_id)"
+		m.AutoscalingGroups.Items[i].Networks = ComponentNamesFromIDs(m.Networks.Items, m.AutoscalingGroups.Items[i].NetworkAWSIDs)
+		m.AutoscalingGroups.Items[i].LaunchConfiguration.SecurityGroups = ComponentNamesFromIDs(m.Firewalls.Items, m.AutoscalingGroups.Items[i].LaunchConfiguration.SecurityGroupAWSIDs)
+	}
+}
+
+// MapDefinitionAutoscalingGroups : Maps output autoscaling groups into a definition defined autoscaling groups
+func MapDefinitionAutoscalingGroups(m *output.FSMMessage) []definition.AutoscalingGroup {
+	var asgs []definition.AutoscalingGroup
+
+	prefix := m.Datacenters.Items[0].Name + "-" + m.ServiceName + "-"
+
+	for _, asg := range m.AutoscalingGroups.Items {
+		sgroups := ComponentNamesFromIDs(m.Firewalls.Items, asg.LaunchConfiguration.SecurityGroupAWSIDs)
+		elbs := append([]string{}, asg.ELBs...)
+
+		asgs = append(asgs, definition.AutoscalingGroup{
+			Name: ShortName(asg.Name, prefix),
+			LaunchConfiguration: definition.LaunchConfiguration{
+				Image:          asg.LaunchConfiguration.Image,
+				Type:           asg.LaunchConfiguration.Type,
+				KeyPair:        asg.LaunchConfiguration.KeyPair,
+				SecurityGroups: ShortNames(sgroups, prefix),
+				UserData:       asg.LaunchConfiguration.UserData,
+			},
+			MinSize:         asg.MinSize,
+			MaxSize:         asg.MaxSize,
+			DesiredCapacity: asg.DesiredCapacity,
+			Networks:        mapDefinitionNetworkNames(m, asg.NetworkAWSIDs, prefix),
+			ELBs:            ShortNames(elbs, prefix),
+		})
+	}
+
+	return asgs
+}
@@ -0,0 +1,109 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapper
+
+import (
+	"testing"
+
+	"github.com/ernestio/aws-definition-mapper/definition"
+	"github.com/ernestio/aws-definition-mapper/output"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMapAutoscalingGroups(t *testing.T) {
+
+	Convey("Given a valid input definition", t, func() {
+		d := definition.Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+		}
+
+		d.AutoscalingGroups = append(d.AutoscalingGroups, definition.AutoscalingGroup{
+			Name: "web",
+			LaunchConfiguration: definition.LaunchConfiguration{
+				Image:          "ami-000000",
+				Type:           "t2.micro",
+				KeyPair:        "key",
+				SecurityGroups: []string{"web-sg"},
+			},
+			MinSize:         1,
+			MaxSize:         3,
+			DesiredCapacity: 2,
+			Networks:        []string{"web"},
+			ELBs:            []string{"web-lb"},
+		})
+
+		Convey("When i try to map autoscaling groups", func() {
+
+			a := MapAutoscalingGroups(d)
+			Convey("Then it should map input autoscaling groups", func() {
+				So(len(a), ShouldEqual, 1)
+				So(a[0].Name, ShouldEqual, "datacenter-service-web")
+				So(a[0].LaunchConfiguration.Image, ShouldEqual, "ami-000000")
+				So(a[0].LaunchConfiguration.Type, ShouldEqual, "t2.micro")
+				So(a[0].LaunchConfiguration.SecurityGroupAWSIDs[0], ShouldEqual, `$(firewalls.items.#[name="datacenter-service-web-sg"].security_group_aws_id)`)
+				So(a[0].NetworkAWSIDs[0], ShouldEqual, `$(networks.items.#[name="datacenter-service-web"].network_aws_id)`)
+				So(a[0].ELBs[0], ShouldEqual, "datacenter-service-web-lb")
+				So(a[0].MinSize, ShouldEqual, 1)
+				So(a[0].MaxSize, ShouldEqual, 3)
+				So(a[0].DesiredCapacity, ShouldEqual, 2)
+				So(a[0].Tags["ernest.service"], ShouldEqual, "service")
+			})
+
+		})
+	})
+
+	Convey("Given a valid output message", t, func() {
+		m := output.FSMMessage{
+			ServiceName: "service",
+		}
+
+		m.Datacenters.Items = append(m.Datacenters.Items, output.Datacenter{
+			Name: "datacenter",
+		})
+
+		m.Networks.Items = append(m.Networks.Items, output.Network{
+			NetworkAWSID: "n-0000000",
+			Name:         "datacenter-service-web",
+			Subnet:       "10.64.0.0/24",
+		})
+
+		m.Firewalls.Items = append(m.Firewalls.Items, output.Firewall{
+			SecurityGroupAWSID: "sg-0000000",
+			Name:               "datacenter-service-web-sg",
+		})
+
+		m.AutoscalingGroups.Items = append(m.AutoscalingGroups.Items, output.AutoscalingGroup{
+			Name: "datacenter-service-web",
+			LaunchConfiguration: output.LaunchConfiguration{
+				Image:               "ami-000000",
+				Type:                "t2.micro",
+				SecurityGroupAWSIDs: []string{"sg-0000000"},
+			},
+			MinSize:         1,
+			MaxSize:         3,
+			DesiredCapacity: 2,
+			NetworkAWSIDs:   []string{"n-0000000"},
+			ELBs:            []string{"datacenter-service-web-lb"},
+		})
+
+		Convey("When i try to map autoscaling groups", func() {
+
+			a := MapDefinitionAutoscalingGroups(&m)
+			Convey("Then it should return a correctly formed set of input autoscaling groups", func() {
+				So(len(a), ShouldEqual, 1)
+				So(a[0].Name, ShouldEqual, "web")
+				So(a[0].LaunchConfiguration.Image, ShouldEqual, "ami-000000")
+				So(a[0].LaunchConfiguration.SecurityGroups, ShouldResemble, []string{"web-sg"})
+				So(a[0].Networks, ShouldResemble, []string{"web"})
+				So(a[0].ELBs, ShouldResemble, []string{"web-lb"})
+				So(a[0].DesiredCapacity, ShouldEqual, 2)
+				So(m.AutoscalingGroups.Items[0].ELBs[0], ShouldEqual, "datacenter-service-web-lb")
+			})
+
+		})
+	})
+
+}
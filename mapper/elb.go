@@ -48,8 +48,8 @@ func MapELBs(d definition.Definition) []output.ELB {
 			})
 		}
 
-		for _, subnet := range elb.Subnets {
-			e.NetworkAWSIDs = append(e.NetworkAWSIDs, `$(networks.items.#[name="`+d.GeneratedName()+subnet+`"].network_aws_id)`)
+		for _, subnet := range mapSubnetNames(d, elb.Subnets) {
+			e.NetworkAWSIDs = append(e.NetworkAWSIDs, `$(networks.items.#[name="`+subnet+`"].network_aws_id)`)
 		}
 
 		for _, instance := range e.Instances {
@@ -98,13 +98,12 @@ func MapDefinitionELBs(m *output.FSMMessage) []definition.ELB {
 	for _, elb := range m.ELBs.Items {
 		instances := ComponentsByIDs(m.Instances.Items, elb.InstanceAWSIDs)
 
-		subnets := ComponentNamesFromIDs(m.Networks.Items, elb.NetworkAWSIDs)
 		sgroups := ComponentNamesFromIDs(m.Firewalls.Items, elb.SecurityGroupAWSIDs)
 
 		e := definition.ELB{
 			Name:           ShortName(elb.Name, prefix),
 			Private:        elb.IsPrivate,
-			Subnets:        ShortNames(subnets, prefix),
+			Subnets:        mapDefinitionNetworkNames(m, elb.NetworkAWSIDs, prefix),
 			Instances:      ComponentGroupsFromIDs(instances, "ernest.instance_group", elb.InstanceAWSIDs),
 			SecurityGroups: ShortNames(sgroups, prefix),
 		}
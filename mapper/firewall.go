@@ -31,11 +31,11 @@ func MapSecurityGroups(d definition.Definition) []output.Firewall {
 		}
 
 		for _, rule := range sg.Ingress {
-			f.Rules.Ingress = append(f.Rules.Ingress, BuildRule(rule))
+			f.Rules.Ingress = append(f.Rules.Ingress, BuildRule(d, rule))
 		}
 
 		for _, rule := range sg.Egress {
-			f.Rules.Egress = append(f.Rules.Egress, BuildRule(rule))
+			f.Rules.Egress = append(f.Rules.Egress, BuildRule(d, rule))
 		}
 
 		firewalls = append(firewalls, f)
@@ -44,29 +44,48 @@ func MapSecurityGroups(d definition.Definition) []output.Firewall {
 }
 
 // BuildRule converts a definition rule into an output rule
-func BuildRule(rule definition.SecurityGroupRule) output.FirewallRule {
+func BuildRule(d definition.Definition, rule definition.SecurityGroupRule) output.FirewallRule {
 	from, _ := strconv.Atoi(rule.FromPort)
 	to, _ := strconv.Atoi(rule.ToPort)
 
-	return output.FirewallRule{
+	r := output.FirewallRule{
 		IP:       rule.IP,
 		From:     from,
 		To:       to,
 		Protocol: MapProtocol(rule.Protocol),
 	}
+
+	// Resolve named networks to their subnet
+	if nw := d.FindNetwork(rule.IP); nw != nil {
+		r.IP = nw.Subnet
+	}
+
+	if rule.SecurityGroup != "" {
+		r.SecurityGroup = d.GeneratedName() + rule.SecurityGroup
+		r.SecurityGroupAWSID = `$(firewalls.items.#[name="` + r.SecurityGroup + `"].security_group_aws_id)`
+	}
+
+	return r
 }
 
 // BuildDefinitionRule converts an output rule into a definition rule
-func BuildDefinitionRule(rule output.FirewallRule) definition.SecurityGroupRule {
+func BuildDefinitionRule(rule output.FirewallRule, prefix string) definition.SecurityGroupRule {
 	from := strconv.Itoa(rule.From)
 	to := strconv.Itoa(rule.To)
 
-	return definition.SecurityGroupRule{
+	r := definition.SecurityGroupRule{
 		IP:       rule.IP,
 		FromPort: from,
 		ToPort:   to,
 		Protocol: MapDefinitionProtocol(rule.Protocol),
 	}
+
+	if rule.SecurityGroup != "" {
+		r.IP = ""
+		r.SecurityGroup = ShortName(rule.SecurityGroup, prefix)
+	}
+
+	return r
 }
 
 // MapProtocol : Maps the security groups protocol to the correct value
@@ -97,11 +116,15 @@ func MapDefinitionSecurityGroups(m *output.FSMMessage) []definition.SecurityGrou
 		}
 
 		for _, rule := range sg.Rules.Ingress {
-			s.Ingress = append(s.Ingress, BuildDefinitionRule(rule))
+			rule.SecurityGroup = mapRuleSecurityGroup(m, rule)
+			rule.IP = mapRuleNetwork(m, rule.IP)
+			s.Ingress = append(s.Ingress, BuildDefinitionRule(rule, prefix))
 		}
 
 		for _, rule := range sg.Rules.Egress {
-			s.Egress = append(s.Egress, BuildDefinitionRule(rule))
+			rule.SecurityGroup = mapRuleSecurityGroup(m, rule)
+			rule.IP = mapRuleNetwork(m, rule.IP)
+			s.Egress = append(s.Egress, BuildDefinitionRule(rule, prefix))
 		}
 
 		sgs = append(sgs, s)
@@ -120,5 +143,39 @@ func UpdateFirewallValues(m *output.FSMMessage) {
 		m.Firewalls.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
 		m.Firewalls.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
 		m.Firewalls.Items[i].VpcID = "$(vpcs.items.0.vpc_id)"
+
+		for x, rule := range m.Firewalls.Items[i].Rules.Ingress {
+			m.Firewalls.Items[i].Rules.Ingress[x].SecurityGroup = mapRuleSecurityGroup(m, rule)
+		}
+
+		for x, rule := range m.Firewalls.Items[i].Rules.Egress {
+			m.Firewalls.Items[i].Rules.Egress[x].SecurityGroup = mapRuleSecurityGroup(m, rule)
+		}
 	}
 }
+
+// mapRuleSecurityGroup returns the name of the security group a rule references
+func mapRuleSecurityGroup(m *output.FSMMessage, rule output.FirewallRule) string {
+	if rule.SecurityGroupAWSID == "" {
+		return rule.SecurityGroup
+	}
+
+	sg := ComponentByID(m.Firewalls.Items, rule.SecurityGroupAWSID)
+	if sg == nil {
+		return rule.SecurityGroup
+	}
+
+	return sg.ComponentName()
+}
+
+// mapRuleNetwork returns the name of the service network matching a rules
+// ip range, or the ip range itself if there is no match
+func mapRuleNetwork(m *output.FSMMessage, ip string) string {
+	for _, nw := range MapDefinitionNetworks(m) {
+		if nw.Subnet == ip {
+			return nw.Name
+		}
+	}
+
+	return ip
+}
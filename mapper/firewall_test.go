@@ -37,6 +37,20 @@ func TestMapSecurityGroups(t *testing.T) {
 			Protocol: "tcp",
 		})
 
+		sg.Ingress = append(sg.Ingress, definition.SecurityGroupRule{
+			SecurityGroup: "web",
+			ToPort:        "5432",
+			FromPort:      "5432",
+			Protocol:      "tcp",
+		})
+
+		sg.Egress = append(sg.Egress, definition.SecurityGroupRule{
+			IP:       "bar",
+			ToPort:   "443",
+			FromPort: "443",
+			Protocol: "tcp",
+		})
+
 		d.SecurityGroups = append(d.SecurityGroups, sg)
 
 		Convey("When i try to map firewalls", func() {
@@ -45,11 +59,16 @@ func TestMapSecurityGroups(t *testing.T) {
 			Convey("Then it should map salt and input firewall rules", func() {
 				So(len(f), ShouldEqual, 1)
 				So(f[0].Name, ShouldEqual, "datacenter-service-test")
-				So(len(f[0].Rules.Ingress), ShouldEqual, 1)
+				So(len(f[0].Rules.Ingress), ShouldEqual, 2)
 				So(f[0].Rules.Ingress[0].IP, ShouldEqual, "10.10.10.11")
 				So(f[0].Rules.Ingress[0].To, ShouldEqual, 80)
 				So(f[0].Rules.Ingress[0].From, ShouldEqual, 80)
 				So(f[0].Rules.Ingress[0].Protocol, ShouldEqual, "tcp")
+				So(f[0].Rules.Ingress[1].IP, ShouldEqual, "")
+				So(f[0].Rules.Ingress[1].SecurityGroup, ShouldEqual, "datacenter-service-web")
+				So(f[0].Rules.Ingress[1].SecurityGroupAWSID, ShouldEqual, `$(firewalls.items.#[name="datacenter-service-web"].security_group_aws_id)`)
+				So(len(f[0].Rules.Egress), ShouldEqual, 1)
+				So(f[0].Rules.Egress[0].IP, ShouldEqual, "10.0.0.0/24")
 				So(f[0].Tags["Name"], ShouldEqual, "datacenter-service-test")
 				So(f[0].Tags["ernest.service"], ShouldEqual, "service")
 			})
@@ -66,6 +85,11 @@ func TestMapSecurityGroups(t *testing.T) {
 			Name: "datacenter",
 		})
 
+		m.Networks.Items = append(m.Networks.Items, output.Network{
+			Name:   "datacenter-service-web",
+			Subnet: "10.0.0.0/24",
+		})
+
 		f := output.Firewall{
 			SecurityGroupAWSID: "sg-0000000",
 			Name:               "datacenter-service-web-sg",
@@ -78,6 +102,13 @@ func TestMapSecurityGroups(t *testing.T) {
 			Protocol: "tcp",
 		})
 
+		f.Rules.Egress = append(f.Rules.Egress, output.FirewallRule{
+			IP:       "10.0.0.0/24",
+			To:       443,
+			From:     443,
+			Protocol: "tcp",
+		})
+
 		f.Rules.Ingress = append(f.Rules.Ingress, output.FirewallRule{
 			IP:       "10.10.10.11",
 			To:       80,
@@ -85,6 +116,13 @@ func TestMapSecurityGroups(t *testing.T) {
 			Protocol: "-1",
 		})
 
+		f.Rules.Ingress = append(f.Rules.Ingress, output.FirewallRule{
+			SecurityGroupAWSID: "sg-0000000",
+			To:                 5432,
+			From:               5432,
+			Protocol:           "tcp",
+		})
+
 		m.Firewalls.Items = append(m.Firewalls.Items, f)
 
 		Convey("When i try to map firewalls", func() {
@@ -94,16 +132,20 @@ func TestMapSecurityGroups(t *testing.T) {
 				So(len(s), ShouldEqual, 1)
 				sg := s[0]
 				So(sg.Name, ShouldEqual, "web-sg")
-				So(len(sg.Egress), ShouldEqual, 1)
+				So(len(sg.Egress), ShouldEqual, 2)
 				So(sg.Egress[0].IP, ShouldEqual, "10.10.10.11")
 				So(sg.Egress[0].FromPort, ShouldEqual, "80")
 				So(sg.Egress[0].ToPort, ShouldEqual, "80")
 				So(sg.Egress[0].Protocol, ShouldEqual, "tcp")
-				So(len(sg.Ingress), ShouldEqual, 1)
+				So(sg.Egress[1].IP, ShouldEqual, "web")
+				So(len(sg.Ingress), ShouldEqual, 2)
 				So(sg.Ingress[0].IP, ShouldEqual, "10.10.10.11")
 				So(sg.Ingress[0].FromPort, ShouldEqual, "80")
 				So(sg.Ingress[0].ToPort, ShouldEqual, "80")
 				So(sg.Ingress[0].Protocol, ShouldEqual, "any")
+				So(sg.Ingress[1].IP, ShouldEqual, "")
+				So(sg.Ingress[1].SecurityGroup, ShouldEqual, "web-sg")
+				So(sg.Ingress[1].FromPort, ShouldEqual, "5432")
 			})
 
 		})
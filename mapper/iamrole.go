@@ -0,0 +1,100 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapper
+
+import (
+	"github.com/ernestio/aws-definition-mapper/definition"
+	"github.com/ernestio/aws-definition-mapper/output"
+)
+
+// DEFAULTASSUMEROLEPOLICY : trust policy used when a role does not specify one, allowing ec2 instances to assume the role
+const DEFAULTASSUMEROLEPOLICY = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}]}`
+
+// MapIAMRoles : Maps the iam roles from a given input payload.
+func MapIAMRoles(d definition.Definition) []output.IAMRole {
+	var roles []output.IAMRole
+
+	for _, role := range d.IAMRoles {
+		name := d.GeneratedName() + role.Name
+
+		r := output.IAMRole{
+			Name:             name,
+			AssumeRolePolicy: role.AssumeRolePolicy,
+			ManagedPolicies:  role.ManagedPolicies,
+			InstanceProfile:  name,
+			Tags:             mapTagsServiceOnly(d.Name),
+			ProviderType:     "$(datacenters.items.0.type)",
+			DatacenterName:   "$(datacenters.items.0.name)",
+			SecretAccessKey:  "$(datacenters.items.0.aws_secret_access_key)",
+			AccessKeyID:      "$(datacenters.items.0.aws_access_key_id)",
+			DatacenterRegion: "$(datacenters.items.0.region)",
+		}
+
+		if r.AssumeRolePolicy == "" {
+			r.AssumeRolePolicy = DEFAULTASSUMEROLEPOLICY
+		}
+
+		for _, p := range role.Policies {
+			r.Policies = append(r.Policies, output.IAMPolicy{
+				Name:     p.Name,
+				Document: p.Document,
+			})
+		}
+
+		roles = append(roles, r)
+	}
+
+	return roles
+}
+
+// UpdateIAMRoleValues corrects missing values after an import
+func UpdateIAMRoleValues(m *output.FSMMessage) {
+	for i := 0; i < len(m.IAMRoles.Items); i++ {
+		m.IAMRoles.Items[i].ProviderType = "$(datacenters.items.0.type)"
+		m.IAMRoles.Items[i].DatacenterName = "$(datacenters.items.0.name)"
+		m.IAMRoles.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+		m.IAMRoles.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+		m.IAMRoles.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
+	}
+}
+
+// MapDefinitionIAMRoles : Maps output iam roles into a definition defined iam roles
+func MapDefinitionIAMRoles(m *output.FSMMessage) []definition.IAMRole {
+	var roles []definition.IAMRole
+
+	prefix := m.Datacenters.Items[0].Name + "-" + m.ServiceName + "-"
+
+	for _, role := range m.IAMRoles.Items {
+		r := definition.IAMRole{
+			Name:             ShortName(role.Name, prefix),
+			AssumeRolePolicy: role.AssumeRolePolicy,
+			ManagedPolicies:  role.ManagedPolicies,
+		}
+
+		if r.AssumeRolePolicy == DEFAULTASSUMEROLEPOLICY {
+			r.AssumeRolePolicy = ""
+		}
+
+		for _, p := range role.Policies {
+			r.Policies = append(r.Policies, definition.IAMPolicy{
+				Name:     p.Name,
+				Document: p.Document,
+			})
+		}
+
+		roles = append(roles, r)
+	}
+
+	return roles
+}
+
+func mapIAMProfileName(m *output.FSMMessage, id string) string {
+	for _, role := range m.IAMRoles.Items {
+		if role.InstanceProfileAWSID == id {
+			return role.InstanceProfile
+		}
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapper
+
+import (
+	"testing"
+
+	"github.com/ernestio/aws-definition-mapper/definition"
+	"github.com/ernestio/aws-definition-mapper/output"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMapIAMRoles(t *testing.T) {
+
+	Convey("Given a valid input definition", t, func() {
+		d := definition.Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+		}
+
+		d.IAMRoles = append(d.IAMRoles, definition.IAMRole{
+			Name: "web",
+			Policies: []definition.IAMPolicy{
+				definition.IAMPolicy{Name: "s3", Document: `{"Statement":[]}`},
+			},
+			ManagedPolicies: []string{"arn:aws:iam::aws:policy/ReadOnlyAccess"},
+		})
+
+		d.Instances = append(d.Instances, definition.Instance{
+			Name:       "web",
+			Network:    "web-nw",
+			Count:      1,
+			IAMProfile: "web",
+		})
+
+		Convey("When i try to map iam roles", func() {
+
+			r := MapIAMRoles(d)
+			Convey("Then it should map input iam roles", func() {
+				So(len(r), ShouldEqual, 1)
+				So(r[0].Name, ShouldEqual, "datacenter-service-web")
+				So(r[0].InstanceProfile, ShouldEqual, "datacenter-service-web")
+				So(r[0].AssumeRolePolicy, ShouldEqual, DEFAULTASSUMEROLEPOLICY)
+				So(r[0].Policies[0].Name, ShouldEqual, "s3")
+				So(r[0].ManagedPolicies[0], ShouldEqual, "arn:aws:iam::aws:policy/ReadOnlyAccess")
+			})
+
+		})
+
+		Convey("When i try to map instances", func() {
+
+			i := MapInstances(d)
+			Convey("Then it should attach the instance profile", func() {
+				So(i[0].IAMProfile, ShouldEqual, "datacenter-service-web")
+				So(i[0].IAMProfileAWSID, ShouldEqual, `$(iam_roles.items.#[name="datacenter-service-web"].instance_profile_aws_id)`)
+			})
+
+		})
+	})
+
+	Convey("Given a valid output message", t, func() {
+		m := output.FSMMessage{
+			ServiceName: "service",
+		}
+
+		m.Datacenters.Items = append(m.Datacenters.Items, output.Datacenter{
+			Name: "datacenter",
+		})
+
+		m.Networks.Items = append(m.Networks.Items, output.Network{
+			NetworkAWSID: "n-0000000",
+			Name:         "datacenter-service-web",
+		})
+
+		m.IAMRoles.Items = append(m.IAMRoles.Items, output.IAMRole{
+			Name:                 "datacenter-service-web",
+			RoleAWSID:            "AROA0000000",
+			AssumeRolePolicy:     DEFAULTASSUMEROLEPOLICY,
+			InstanceProfile:      "datacenter-service-web",
+			InstanceProfileAWSID: "AIPA0000000",
+		})
+
+		m.Instances.Items = append(m.Instances.Items, output.Instance{
+			InstanceAWSID:   "i-0000000",
+			Name:            "datacenter-service-web-1",
+			NetworkAWSID:    "n-0000000",
+			IAMProfileAWSID: "AIPA0000000",
+			Tags:            map[string]string{"ernest.instance_group": "web"},
+		})
+
+		Convey("When i try to map iam roles", func() {
+
+			r := MapDefinitionIAMRoles(&m)
+			Convey("Then it should return a correctly formed set of input iam roles", func() {
+				So(len(r), ShouldEqual, 1)
+				So(r[0].Name, ShouldEqual, "web")
+				So(r[0].AssumeRolePolicy, ShouldEqual, "")
+			})
+
+		})
+
+		Convey("When i try to map instances", func() {
+
+			i := MapDefinitionInstances(&m)
+			Convey("Then it should return the instance profile", func() {
+				So(i[0].IAMProfile, ShouldEqual, "web")
+			})
+
+		})
+	})
+
+}
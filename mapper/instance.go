@@ -16,10 +16,9 @@ import (
 func MapInstances(d definition.Definition) []output.Instance {
 	var instances []output.Instance
 
-	for _, instance := range d.Instances {
-		ip := make(net.IP, net.IPv4len)
-		copy(ip, instance.StartIP.To4())
+	ips, _ := d.InstanceIPs()
 
+	for _, instance := range d.Instances {
 		for i := 0; i < instance.Count; i++ {
 			var sgroups []string
 			for _, sg := range instance.SecurityGroups {
@@ -27,14 +26,15 @@ func MapInstances(d definition.Definition) []output.Instance {
 			}
 
 			name := d.GeneratedName() + instance.Name + "-" + strconv.Itoa(i+1)
+			network := mapInstanceNetwork(d, instance.Network, i)
 
 			newInstance := output.Instance{
 				Name:                name,
 				Type:                instance.Type,
 				Image:               instance.Image,
-				Network:             d.GeneratedName() + instance.Network,
-				NetworkAWSID:        `$(networks.items.#[name="` + d.GeneratedName() + instance.Network + `"].network_aws_id)`,
-				IP:                  net.ParseIP(ip.String()),
+				Network:             network,
+				NetworkAWSID:        `$(networks.items.#[name="` + network + `"].network_aws_id)`,
+				IP:                  instanceIP(ips[instance.Name], i),
 				KeyPair:             instance.KeyPair,
 				AssignElasticIP:     instance.ElasticIP,
 				SecurityGroups:      sgroups,
@@ -60,10 +60,12 @@ func MapInstances(d definition.Definition) []output.Instance {
 				newInstance.Volumes = append(newInstance.Volumes, v)
 			}
 
-			instances = append(instances, newInstance)
+			if instance.IAMProfile != "" {
+				newInstance.IAMProfile = d.GeneratedName() + instance.IAMProfile
+				newInstance.IAMProfileAWSID = `$(iam_roles.items.#[name="` + newInstance.IAMProfile + `"].instance_profile_aws_id)`
+			}
 
-			// Increment IP address
-			ip[3]++
+			instances = append(instances, newInstance)
 		}
 	}
 	return instances
@@ -79,6 +81,7 @@ func UpdateInstanceValues(m *output.FSMMessage) {
 		m.Instances.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
 		m.Instances.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
 		m.Instances.Items[i].VpcID = "$(vpcs.items.0.vpc_id)"
+		m.Instances.Items[i].AssignElasticIP = m.Instances.Items[i].ElasticIP != ""
 
 		nw := ComponentByID(m.Networks.Items, m.Instances.Items[i].NetworkAWSID)
 		if nw != nil {
@@ -87,6 +90,10 @@ func UpdateInstanceValues(m *output.FSMMessage) {
 
 		m.Instances.Items[i].SecurityGroups = ComponentNamesFromIDs(m.Firewalls.Items, m.Instances.Items[i].SecurityGroupAWSIDs)
 
+		if m.Instances.Items[i].IAMProfileAWSID != "" {
+			m.Instances.Items[i].IAMProfile = mapIAMProfileName(m, m.Instances.Items[i].IAMProfileAWSID)
+		}
+
 		for x := 0; x < len(m.Instances.Items[i].Volumes); x++ {
 			v := ComponentByID(m.EBSVolumes.Items, m.Instances.Items[i].Volumes[x].VolumeAWSID)
 			if v != nil {
@@ -123,7 +130,7 @@ func MapDefinitionInstances(m *output.FSMMessage) []definition.Instance {
 			Name:           ig,
 			Type:           firstInstance.Type,
 			Image:          firstInstance.Image,
-			Network:        ShortName(network.ComponentName(), prefix),
+			Network:        ShortName(firstInstance.Network, prefix),
 			StartIP:        firstInstance.IP,
 			KeyPair:        firstInstance.KeyPair,
 			SecurityGroups: ShortNames(sgroups, prefix),
@@ -131,6 +138,21 @@ func MapDefinitionInstances(m *output.FSMMessage) []definition.Instance {
 			Count:          len(is),
 		}
 
+		if network != nil {
+			instance.Network = ShortName(network.ComponentName(), prefix)
+
+			// instances spread across availability zones are mapped to the
+			// network their subnets were expanded from
+			if group := network.GetTags()["ernest.network"]; group != "" {
+				instance.Network = group
+				instance.StartIP = nil
+			}
+		}
+
+		if firstInstance.IAMProfileAWSID != "" {
+			instance.IAMProfile = ShortName(mapIAMProfileName(m, firstInstance.IAMProfileAWSID), prefix)
+		}
+
 		for _, vol := range firstInstance.Volumes {
 			vc := ComponentByID(m.EBSVolumes.Items, vol.VolumeAWSID)
 			if vc == nil {
@@ -152,6 +174,13 @@ func MapDefinitionInstances(m *output.FSMMessage) []definition.Instance {
 	return instances
 }
 
+func instanceIP(ips []net.IP, i int) net.IP {
+	if i >= len(ips) {
+		return nil
+	}
+	return ips[i]
+}
+
 func mapInstanceSecurityGroupIDs(sgs []string) []string {
 	var ids []string
 
@@ -5,6 +5,7 @@
 package mapper
 
 import (
+	"net"
 	"testing"
 
 	"github.com/ernestio/aws-definition-mapper/definition"
@@ -87,6 +88,93 @@ func TestInstancesMapping(t *testing.T) {
 					So(i[1].Tags["ernest.instance_group"], ShouldEqual, "foo")
 				})
 			})
+
+			Convey("And no start ip is set", func() {
+				d.Instances[0].Count = 2
+				d.Instances = append(d.Instances, definition.Instance{
+					Name:    "web",
+					Count:   1,
+					Network: "bar",
+					StartIP: net.ParseIP("10.0.0.5"),
+				})
+				i := MapInstances(d)
+				Convey("Then it should allocate free ips that are not reserved", func() {
+					So(len(i), ShouldEqual, 3)
+					So(i[0].IP.String(), ShouldEqual, "10.0.0.4")
+					So(i[1].IP.String(), ShouldEqual, "10.0.0.6")
+					So(i[2].IP.String(), ShouldEqual, "10.0.0.5")
+				})
+			})
+
+			Convey("And a previous build allocated ips to the instances", func() {
+				var om output.FSMMessage
+				om.Instances.Items = []output.Instance{
+					{Name: "datacenter-service-foo-1", IP: net.ParseIP("10.0.0.4")},
+					{Name: "datacenter-service-web-1", IP: net.ParseIP("10.0.0.5")},
+				}
+				d.Instances = append(d.Instances, definition.Instance{
+					Name:    "web",
+					Count:   1,
+					Network: "bar",
+				})
+				d.Instances[0].Count = 2
+				AllocateSubnets(&d, &om)
+				i := MapInstances(d)
+				Convey("Then existing instances should keep their ips", func() {
+					So(len(i), ShouldEqual, 3)
+					So(i[0].IP.String(), ShouldEqual, "10.0.0.4")
+					So(i[1].IP.String(), ShouldEqual, "10.0.0.6")
+					So(i[2].IP.String(), ShouldEqual, "10.0.0.5")
+				})
+			})
+
+			Convey("And the allocated ips cross an octet boundary", func() {
+				d.Networks[0].Subnet = "10.0.0.0/16"
+				d.Instances[0].Count = 3
+				d.Instances[0].StartIP = net.ParseIP("10.0.0.254")
+				i := MapInstances(d)
+				Convey("Then it should carry over to the next octet", func() {
+					So(len(i), ShouldEqual, 3)
+					So(i[0].IP.String(), ShouldEqual, "10.0.0.254")
+					So(i[1].IP.String(), ShouldEqual, "10.0.0.255")
+					So(i[2].IP.String(), ShouldEqual, "10.0.1.0")
+				})
+			})
+		})
+	})
+
+	Convey("Given an instance on a network across multiple availability zones", t, func() {
+		d := definition.Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+		}
+
+		d.Networks = append(d.Networks, definition.Network{
+			Name:              "bar",
+			Subnet:            "10.0.0.0/24",
+			AvailabilityZones: []string{"eu-west-1a", "eu-west-1b"},
+		})
+
+		d.Instances = append(d.Instances, definition.Instance{
+			Name:    "foo",
+			Type:    "m1.small",
+			Image:   "ami-000000",
+			Count:   3,
+			Network: "bar",
+		})
+
+		Convey("When i try to map instances", func() {
+			i := MapInstances(d)
+			Convey("Then the instances should be spread across the availability zone subnets", func() {
+				So(len(i), ShouldEqual, 3)
+				So(i[0].Network, ShouldEqual, "datacenter-service-bar-a")
+				So(i[0].NetworkAWSID, ShouldEqual, `$(networks.items.#[name="datacenter-service-bar-a"].network_aws_id)`)
+				So(i[0].IP.String(), ShouldEqual, "10.0.0.4")
+				So(i[1].Network, ShouldEqual, "datacenter-service-bar-b")
+				So(i[1].IP.String(), ShouldEqual, "10.0.0.132")
+				So(i[2].Network, ShouldEqual, "datacenter-service-bar-a")
+				So(i[2].IP.String(), ShouldEqual, "10.0.0.5")
+			})
 		})
 	})
 
@@ -25,9 +25,13 @@ func ConvertPayload(p *definition.Payload) *output.FSMMessage {
 	// Map VPCs
 	m.VPCs.Items = MapVPCs(p)
 
-	// Map networks
+	// Map networks, allocating subnets to any network that only specifies a size
+	p.Service.AllocateSubnets(nil)
 	m.Networks.Items = MapNetworks(p.Service)
 
+	// Map iam roles
+	m.IAMRoles.Items = MapIAMRoles(p.Service)
+
 	// Map instances
 	m.Instances.Items = MapInstances(p.Service)
 
@@ -40,6 +44,12 @@ func ConvertPayload(p *definition.Payload) *output.FSMMessage {
 	// Map ELB's
 	m.ELBs.Items = MapELBs(p.Service)
 
+	// Map ALB's
+	m.ALBs.Items = MapALBs(p.Service)
+
+	// Map autoscaling groups
+	m.AutoscalingGroups.Items = MapAutoscalingGroups(p.Service)
+
 	// Map S3 buckets
 	m.S3s.Items = MapS3Buckets(p.Service)
 
@@ -71,12 +81,18 @@ func ConvertFSMMessage(m *output.FSMMessage) *definition.Definition {
 
 	d.Instances = MapDefinitionInstances(m)
 
+	d.IAMRoles = MapDefinitionIAMRoles(m)
+
 	d.SecurityGroups = MapDefinitionSecurityGroups(m)
 
 	d.NatGateways = MapDefinitionNats(m)
 
 	d.ELBs = MapDefinitionELBs(m)
 
+	d.ALBs = MapDefinitionALBs(m)
+
+	d.AutoscalingGroups = MapDefinitionAutoscalingGroups(m)
+
 	d.EBSVolumes = MapDefinitionEBSVolumes(m)
 
 	d.S3Buckets = MapDefinitionS3Buckets(m)
@@ -93,9 +109,12 @@ func ConvertFSMMessage(m *output.FSMMessage) *definition.Definition {
 // UpdateFSMMessageValues : Load all missing values for components
 func UpdateFSMMessageValues(m *output.FSMMessage) {
 	UpdateNetworkValues(m)
+	UpdateIAMRoleValues(m)
 	UpdateInstanceValues(m)
 	UpdateEBSValues(m)
 	UpdateELBValues(m)
+	UpdateALBValues(m)
+	UpdateAutoscalingGroupValues(m)
 	UpdateFirewallValues(m)
 	UpdateNatValues(m)
 	UpdateRDSClusterValues(m)
@@ -104,146 +123,121 @@ func UpdateFSMMessageValues(m *output.FSMMessage) {
 	UpdateS3Values(m)
 }
 
-// MapProviderData will map any information generated by a provider that is not
-// deductible from the input definition
-func MapProviderData(m, om *output.FSMMessage) {
-	// Map network ID's
-	for i, network := range m.Networks.Items {
-		nw := om.FindNetwork(network.Name)
-		if nw != nil {
-			m.Networks.Items[i].NetworkAWSID = nw.NetworkAWSID
-			m.Networks.Items[i].AvailabilityZone = nw.AvailabilityZone
-			m.Networks.Items[i].DatacenterType = "$(datacenters.items.0.type)"
-			m.Networks.Items[i].DatacenterName = "$(datacenters.items.0.name)"
-			m.Networks.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
-			m.Networks.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
-			m.Networks.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
-			m.Networks.Items[i].VpcID = "$(vpcs.items.0.vpc_id)"
-		}
+// ValidateChanges : Validates the changes a definition makes to the
+// components of a previous build. Only the components that restrict how
+// they can be changed are mapped from the previous build
+func ValidateChanges(d *definition.Definition, om *output.FSMMessage) error {
+	previous := definition.Definition{
+		EBSVolumes: MapDefinitionEBSVolumes(om),
 	}
 
-	// Map instance ID's
-	for i, instance := range m.Instances.Items {
-		in := om.FindInstance(instance.Name)
-		if in != nil {
-			m.Instances.Items[i].InstanceAWSID = in.InstanceAWSID
-			m.Instances.Items[i].PublicIP = in.PublicIP
-			m.Instances.Items[i].ElasticIP = in.ElasticIP
-			m.Instances.Items[i].ElasticIPAWSID = in.ElasticIPAWSID
-			m.Instances.Items[i].DatacenterType = "$(datacenters.items.0.type)"
-			m.Instances.Items[i].DatacenterName = "$(datacenters.items.0.name)"
-			m.Instances.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
-			m.Instances.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
-			m.Instances.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
-			m.Instances.Items[i].VpcID = "$(vpcs.items.0.vpc_id)"
-		}
+	if len(om.Datacenters.Items) > 0 {
+		previous.Networks = MapDefinitionNetworks(om)
 	}
 
-	// Map firewall ID's
-	for i, firewall := range m.Firewalls.Items {
-		fw := om.FindFirewall(firewall.Name)
-		if fw != nil {
-			m.Firewalls.Items[i].SecurityGroupAWSID = fw.SecurityGroupAWSID
-			m.Firewalls.Items[i].ProviderType = "$(datacenters.items.0.type)"
-			m.Firewalls.Items[i].DatacenterType = "$(datacenters.items.0.type)"
-			m.Firewalls.Items[i].DatacenterName = "$(datacenters.items.0.name)"
-			m.Firewalls.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
-			m.Firewalls.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
-			m.Firewalls.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
-			m.Firewalls.Items[i].VpcID = "$(vpcs.items.0.vpc_id)"
-		}
+	return d.ValidateChanges(&previous)
+}
+
+// MapChanges : Maps a payload to a fsm message holding the changes it makes
+// to the previous build of its service. The definition is validated first,
+// and any error is returned as the response sent in place of a mapping
+func MapChanges(p *definition.Payload, om *output.FSMMessage) (*output.FSMMessage, *output.Error) {
+	if p.Service.VpcID != "" && len(om.VPCs.Items) > 0 && p.Service.VpcID != om.VPCs.Items[0].VpcID {
+		return nil, output.NewError(output.ERRVPCIMMUTABLE, "VPC ID cannot change between builds.", nil)
 	}
 
-	// Map nat ID's
-	for i, nat := range m.Nats.Items {
-		nt := om.FindNat(nat.Name)
-		if nt != nil {
-			m.Nats.Items[i].NatGatewayAWSID = nt.NatGatewayAWSID
-			m.Nats.Items[i].NatGatewayAllocationID = nt.NatGatewayAllocationID
-			m.Nats.Items[i].NatGatewayAllocationIP = nt.NatGatewayAllocationIP
-			m.Nats.Items[i].ProviderType = "$(datacenters.items.0.type)"
-			m.Nats.Items[i].DatacenterType = "$(datacenters.items.0.type)"
-			m.Nats.Items[i].DatacenterName = "$(datacenters.items.0.name)"
-			m.Nats.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
-			m.Nats.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
-			m.Nats.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
-			m.Nats.Items[i].VpcID = "$(vpcs.items.0.vpc_id)"
-		}
+	// Allocate subnets to networks that only specify a size
+	AllocateSubnets(&p.Service, om)
+
+	if err := p.Service.Validate(); err != nil {
+		return nil, ValidationError(err)
 	}
 
-	// Map elb data
-	for i, elb := range m.ELBs.Items {
-		lb := om.FindELB(elb.Name)
-		if lb != nil {
-			m.ELBs.Items[i].DNSName = lb.DNSName
-			m.ELBs.Items[i].Type = "$(datacenters.items.0.type)"
-			m.ELBs.Items[i].DatacenterType = "$(datacenters.items.0.type)"
-			m.ELBs.Items[i].DatacenterName = "$(datacenters.items.0.name)"
-			m.ELBs.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
-			m.ELBs.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
-			m.ELBs.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
-			m.ELBs.Items[i].VpcID = "$(vpcs.items.0.vpc_id)"
-		}
+	if err := ValidateChanges(&p.Service, om); err != nil {
+		return nil, ValidationError(err)
 	}
 
-	// Map ebs data
-	for i, ebs := range m.EBSVolumes.Items {
-		volume := om.FindEBSVolume(ebs.Name)
-		if volume != nil {
-			m.EBSVolumes.Items[i].VolumeAWSID = volume.VolumeAWSID
-			m.EBSVolumes.Items[i].DatacenterType = "$(datacenters.items.0.type)"
-			m.EBSVolumes.Items[i].DatacenterName = "$(datacenters.items.0.name)"
-			m.EBSVolumes.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
-			m.EBSVolumes.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
-			m.EBSVolumes.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
-		}
+	// new fsm message
+	m := ConvertPayload(p)
+
+	// Map provider data from previous build
+	MapProviderData(m, om)
+
+	// Check for changes
+	m.Diff(*om)
+
+	return m, nil
+}
+
+// MapCreation : Maps a payload to the fsm message of a creation request,
+// with the workflow applying its changes to the previous build
+func MapCreation(p *definition.Payload, om *output.FSMMessage) (*output.FSMMessage, *output.Error) {
+	m, e := MapChanges(p, om)
+	if e != nil {
+		return nil, e
 	}
 
-	for i, zone := range m.Route53s.Items {
-		z := om.FindRoute53(zone.Name)
-		if z != nil {
-			m.Route53s.Items[i].HostedZoneID = z.HostedZoneID
-			m.Route53s.Items[i].DatacenterName = "$(datacenters.items.0.name)"
-			m.Route53s.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
-			m.Route53s.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
-			m.Route53s.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
-			m.Route53s.Items[i].VPCID = "$(vpcs.items.0.vpc_id)"
-		}
+	if err := m.GenerateWorkflow("create"); err != nil {
+		return nil, output.NewError(output.ERRWORKFLOWGENERATIONFAILED, "Could not generate workflow: "+err.Error(), nil)
 	}
 
-	for i, s3 := range m.S3s.Items {
-		z := om.FindS3(s3.Name)
-		if z != nil {
-			m.S3s.Items[i].DatacenterName = "$(datacenters.items.0.name)"
-			m.S3s.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
-			m.S3s.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
-			m.S3s.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
-		}
+	return m, nil
+}
+
+// ValidationError : Builds the error response of a definition that is not
+// valid, listing every validation error it has
+func ValidationError(err error) *output.Error {
+	if errs, ok := err.(definition.ValidationErrors); ok {
+		return output.NewError(output.ERRVALIDATIONFAILED, err.Error(), errs)
 	}
+	return output.NewError(output.ERRVALIDATIONFAILED, err.Error(), nil)
+}
 
-	for i, cluster := range m.RDSClusters.Items {
-		c := om.FindRDSCluster(cluster.Name)
-		if c != nil {
-			m.RDSClusters.Items[i].ARN = c.ARN
-			m.RDSClusters.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
-			m.RDSClusters.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
-			m.RDSClusters.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
-			m.RDSClusters.Items[i].Endpoint = c.Endpoint
+// MapProviderData will map any information generated by a provider that is not
+// deductible from the input definition
+func MapProviderData(m, om *output.FSMMessage) {
+	for _, t := range output.ComponentTypes {
+		if t.ProviderData == nil {
+			continue
 		}
-	}
 
-	for i, instance := range m.RDSInstances.Items {
-		in := om.FindRDSInstance(instance.Name)
-		if in != nil {
-			m.RDSInstances.Items[i].ARN = in.ARN
-			m.RDSInstances.Items[i].SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
-			m.RDSInstances.Items[i].AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
-			m.RDSInstances.Items[i].DatacenterRegion = "$(datacenters.items.0.region)"
-			m.RDSInstances.Items[i].Endpoint = in.Endpoint
+		for _, c := range m.Components(t.Collection) {
+			o := om.Find(t.Collection, c.ComponentName())
+			if o == nil {
+				continue
+			}
+
+			// a replacement is a new component, so it keeps none of the
+			// values generated for the component it replaces
+			if t.HasChanged != nil && output.RequiresReplacement(t.HasChanged(c, o)) {
+				continue
+			}
+
+			t.ProviderData(c, o)
 		}
 	}
 }
 
+// Rollback : Builds a message that returns a service to the components of
+// its previous mapping, reverting any changes a partially applied build has
+// made. Components the build created are deleted, components it deleted are
+// created again and components it updated are reverted
+func Rollback(m, om *output.FSMMessage) *output.FSMMessage {
+	applied := m.Applied(*om)
+
+	r := om.State()
+
+	// the rollback is applied as part of the failed build
+	r.ID = m.ID
+	r.Service = m.Service
+
+	MapProviderData(r, applied)
+
+	r.Diff(*applied)
+
+	return r
+}
+
 func mapTags(name, service string) map[string]string {
 	tags := make(map[string]string)
 
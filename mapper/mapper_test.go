@@ -0,0 +1,267 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapper
+
+import (
+	"testing"
+
+	"github.com/ernestio/aws-definition-mapper/definition"
+	"github.com/ernestio/aws-definition-mapper/output"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMapProviderData(t *testing.T) {
+	Convey("Given a mapping and a previous mapping of the same service", t, func() {
+		var m, om output.FSMMessage
+
+		m.Networks.Items = []output.Network{{Name: "web"}, {Name: "db"}}
+		m.IAMRoles.Items = []output.IAMRole{{Name: "web"}}
+		m.Route53s.Items = []output.Route53Zone{{Name: "example.com"}}
+		om.Networks.Items = []output.Network{{Name: "web", NetworkAWSID: "subnet-1", AvailabilityZone: "eu-west-1a"}}
+		om.IAMRoles.Items = []output.IAMRole{{Name: "web", RoleAWSID: "role-1", InstanceProfileAWSID: "profile-1"}}
+		om.Route53s.Items = []output.Route53Zone{{Name: "example.com", HostedZoneID: "zone-1"}}
+
+		Convey("When mapping provider data", func() {
+			MapProviderData(&m, &om)
+
+			Convey("Then it should copy the aws values of existing components", func() {
+				So(m.Networks.Items[0].NetworkAWSID, ShouldEqual, "subnet-1")
+				So(m.Networks.Items[0].AvailabilityZone, ShouldEqual, "eu-west-1a")
+				So(m.Networks.Items[0].VpcID, ShouldEqual, "$(vpcs.items.0.vpc_id)")
+				So(m.Networks.Items[1].NetworkAWSID, ShouldEqual, "")
+				So(m.IAMRoles.Items[0].RoleAWSID, ShouldEqual, "role-1")
+				So(m.IAMRoles.Items[0].InstanceProfileAWSID, ShouldEqual, "profile-1")
+				So(m.Route53s.Items[0].HostedZoneID, ShouldEqual, "zone-1")
+			})
+		})
+	})
+}
+
+func TestRollback(t *testing.T) {
+	Convey("Given a previous mapping", t, func() {
+		var om output.FSMMessage
+		om.ID = "build-1"
+		om.Instances.Items = []output.Instance{
+			{Name: "web-1", Type: "t2.micro", Image: "ami-000000", InstanceAWSID: "i-1"},
+			{Name: "web-2", Type: "t2.micro", Image: "ami-000000", InstanceAWSID: "i-2"},
+			{Name: "web-3", Type: "t2.micro", Image: "ami-000000", InstanceAWSID: "i-3"},
+		}
+
+		Convey("When a build has failed after changing some of its components", func() {
+			var m output.FSMMessage
+			m.ID = "build-2"
+			m.Service = "build-2"
+			m.InstancesToCreate.Items = []output.Instance{
+				{Name: "web-3", Type: "t2.micro", Image: "ami-111111", InstanceAWSID: "i-5", Status: "completed"},
+				{Name: "web-4", Type: "t2.micro", Image: "ami-000000", InstanceAWSID: "i-4", Status: "completed"},
+				{Name: "web-5", Type: "t2.micro", Image: "ami-000000", Status: "errored"},
+			}
+			m.InstancesToUpdate.Items = []output.Instance{
+				{Name: "web-1", Type: "t2.large", Image: "ami-000000", InstanceAWSID: "i-1", Status: "completed"},
+			}
+			m.InstancesToDelete.Items = []output.Instance{
+				{Name: "web-2", Type: "t2.micro", Image: "ami-000000", InstanceAWSID: "i-2", Status: "completed"},
+				{Name: "web-3", Type: "t2.micro", Image: "ami-000000", InstanceAWSID: "i-3", Status: "completed"},
+			}
+
+			r := Rollback(&m, &om)
+
+			Convey("Then it should be applied as part of the failed build", func() {
+				So(r.ID, ShouldEqual, "build-2")
+				So(r.Service, ShouldEqual, "build-2")
+			})
+
+			Convey("Then it should delete the components the build created", func() {
+				So(len(r.InstancesToDelete.Items), ShouldEqual, 2)
+				So(r.InstancesToDelete.Items[0].InstanceAWSID, ShouldEqual, "i-5")
+				So(r.InstancesToDelete.Items[1].InstanceAWSID, ShouldEqual, "i-4")
+			})
+
+			Convey("Then it should create the components the build deleted again", func() {
+				So(len(r.InstancesToCreate.Items), ShouldEqual, 2)
+				So(r.InstancesToCreate.Items[0].Name, ShouldEqual, "web-2")
+				So(r.InstancesToCreate.Items[1].Name, ShouldEqual, "web-3")
+				So(r.InstancesToCreate.Items[1].Image, ShouldEqual, "ami-000000")
+				So(r.IsReplaced("instances", "web-3"), ShouldBeTrue)
+			})
+
+			Convey("Then it should revert the components the build updated", func() {
+				So(len(r.InstancesToUpdate.Items), ShouldEqual, 1)
+				So(r.InstancesToUpdate.Items[0].Type, ShouldEqual, "t2.micro")
+				So(r.InstancesToUpdate.Items[0].InstanceAWSID, ShouldEqual, "i-1")
+				So(r.InstancesToUpdate.Items[0].Changes, ShouldResemble, []output.Change{{Field: "instance_type", Old: "t2.large", New: "t2.micro"}})
+			})
+
+			Convey("Then it should generate a workflow", func() {
+				So(r.GenerateWorkflow("create"), ShouldBeNil)
+				So(r.Workflow.Arcs, ShouldNotBeEmpty)
+			})
+		})
+	})
+}
+
+func TestValidateChanges(t *testing.T) {
+	Convey("Given a definition and a previous mapping of the same service", t, func() {
+		size := int64(10)
+		d := definition.Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+			EBSVolumes: []definition.EBSVolume{{Name: "data", Type: "gp2", Size: &size, Count: 1}},
+		}
+
+		previous := int64(20)
+		var om output.FSMMessage
+		om.Datacenters.Items = []output.Datacenter{{Name: "datacenter"}}
+		om.Instances.Items = []output.Instance{{Name: "datacenter-service-web-1", NetworkAWSID: `$(networks.items.#[name="datacenter-service-web"].network_aws_id)`}}
+		om.EBSVolumes.Items = []output.EBSVolume{{Name: "datacenter-service-data-1", VolumeType: "gp2", Size: &previous, Tags: map[string]string{"ernest.volume_group": "data"}}}
+
+		Convey("When validating the changes", func() {
+			err := ValidateChanges(&d, &om)
+
+			Convey("Then it should only compare the restricted components", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "EBS Volume size can't be decreased from 20 to 10 (GB)")
+			})
+		})
+
+		Convey("When an availability zone is added to a network with instances", func() {
+			d.EBSVolumes[0].Size = &previous
+			d.Networks = []definition.Network{{Name: "web", Subnet: "10.0.0.0/24", AvailabilityZones: []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"}}}
+			d.Instances = []definition.Instance{{Name: "web", Network: "web", Count: 1}}
+			om.Networks.Items = []output.Network{
+				{Name: "datacenter-service-web-a", Subnet: "10.0.0.0/25", AvailabilityZone: "eu-west-1a", Tags: map[string]string{"ernest.network": "web"}},
+				{Name: "datacenter-service-web-b", Subnet: "10.0.0.128/25", AvailabilityZone: "eu-west-1b", Tags: map[string]string{"ernest.network": "web"}},
+			}
+			err := ValidateChanges(&d, &om)
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.(definition.ValidationErrors)[0].Field, ShouldEqual, "availability_zones")
+			})
+		})
+	})
+}
+
+func TestReplacedInstanceReferences(t *testing.T) {
+	Convey("Given a service with load balancers and dns records pointing at its instances", t, func() {
+		d := definition.Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+			Networks:   []definition.Network{{Name: "web", Subnet: "10.0.0.0/24"}},
+			Instances:  []definition.Instance{{Name: "web", Image: "ami-000000", Count: 1, Network: "web"}},
+			ELBs:       []definition.ELB{{Name: "web-elb", Instances: []string{"web"}}},
+			ALBs: []definition.ALB{{
+				Name:         "web-alb",
+				TargetGroups: []definition.ALBTargetGroup{{Name: "web", Instances: []string{"web"}}},
+			}},
+			Route53Zones: []definition.Route53Zone{{
+				Name:    "example.com",
+				Records: []definition.Record{{Entry: "web.example.com", Type: "A", Instances: []string{"web-1"}}},
+			}},
+		}
+
+		om := output.FSMMessage{}
+		om.Instances.Items = MapInstances(d)
+		om.ELBs.Items = MapELBs(d)
+		om.ALBs.Items = MapALBs(d)
+		om.Route53s.Items = MapRoute53Zones(d)
+
+		Convey("When an instance is replaced", func() {
+			d.Instances[0].Image = "ami-111111"
+
+			m := output.FSMMessage{}
+			m.Instances.Items = MapInstances(d)
+			m.ELBs.Items = MapELBs(d)
+			m.ALBs.Items = MapALBs(d)
+			m.Route53s.Items = MapRoute53Zones(d)
+
+			m.Diff(om)
+
+			Convey("Then every component referencing it should be updated", func() {
+				So(m.IsReplaced("instances", "datacenter-service-web-1"), ShouldBeTrue)
+				change := []output.Change{{Field: "instances", Old: "datacenter-service-web-1", New: "datacenter-service-web-1"}}
+				So(len(m.ELBsToUpdate.Items), ShouldEqual, 1)
+				So(m.ELBsToUpdate.Items[0].Changes, ShouldResemble, change)
+				So(len(m.ALBsToUpdate.Items), ShouldEqual, 1)
+				So(m.ALBsToUpdate.Items[0].Changes, ShouldResemble, change)
+				So(len(m.Route53sToUpdate.Items), ShouldEqual, 1)
+				So(m.Route53sToUpdate.Items[0].Changes, ShouldResemble, change)
+			})
+		})
+
+		Convey("When an instance is updated in place", func() {
+			d.Instances[0].Type = "t2.large"
+
+			m := output.FSMMessage{}
+			m.Instances.Items = MapInstances(d)
+			m.ELBs.Items = MapELBs(d)
+			m.ALBs.Items = MapALBs(d)
+			m.Route53s.Items = MapRoute53Zones(d)
+
+			m.Diff(om)
+
+			Convey("Then the components referencing it should not be updated", func() {
+				So(len(m.InstancesToUpdate.Items), ShouldEqual, 1)
+				So(len(m.ELBsToUpdate.Items), ShouldEqual, 0)
+				So(len(m.ALBsToUpdate.Items), ShouldEqual, 0)
+				So(len(m.Route53sToUpdate.Items), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestMapCreation(t *testing.T) {
+	Convey("Given a payload", t, func() {
+		p := definition.Payload{
+			ServiceID:  "service-1",
+			Datacenter: definition.Datacenter{Name: "datacenter", Type: "aws", Region: "eu-west-1"},
+			Service: definition.Definition{
+				Name:       "service",
+				Datacenter: "datacenter",
+				VpcSubnet:  "10.0.0.0/16",
+				Networks:   []definition.Network{{Name: "web", Subnet: "10.0.0.0/24"}},
+				Instances:  []definition.Instance{{Name: "web", Type: "t2.micro", Image: "ami-000000", Count: 1, Network: "web"}},
+			},
+		}
+		p.Service.DatacenterDetails = p.Datacenter
+
+		Convey("When mapping a valid definition", func() {
+			m, e := MapCreation(&p, &output.FSMMessage{})
+
+			Convey("Then it should return the changes and their workflow", func() {
+				So(e, ShouldBeNil)
+				So(len(m.NetworksToCreate.Items), ShouldEqual, 1)
+				So(len(m.InstancesToCreate.Items), ShouldEqual, 1)
+				So(len(m.Workflow.Arcs), ShouldBeGreaterThan, 0)
+			})
+		})
+
+		Convey("When mapping a definition with several invalid components", func() {
+			p.Service.Name = ""
+			p.Service.Instances[0].Image = ""
+			m, e := MapCreation(&p, &output.FSMMessage{})
+
+			Convey("Then it should return every validation error", func() {
+				So(m, ShouldBeNil)
+				So(e.Code, ShouldEqual, output.ERRVALIDATIONFAILED)
+				errs := e.Details.(definition.ValidationErrors)
+				So(len(errs), ShouldEqual, 2)
+				So(errs[0].Field, ShouldEqual, "name")
+				So(errs[1].Field, ShouldEqual, "image")
+			})
+		})
+
+		Convey("When mapping a definition that changes the vpc of the previous build", func() {
+			var om output.FSMMessage
+			om.VPCs.Items = []output.VPC{{VpcID: "vpc-1"}}
+			p.Service.VpcID = "vpc-2"
+			_, e := MapCreation(&p, &om)
+
+			Convey("Then it should return an error", func() {
+				So(e.Code, ShouldEqual, output.ERRVPCIMMUTABLE)
+			})
+		})
+	})
+}
@@ -5,32 +5,51 @@
 package mapper
 
 import (
+	"strings"
+
 	"github.com/ernestio/aws-definition-mapper/definition"
 	"github.com/ernestio/aws-definition-mapper/output"
 )
 
-// MapNats : Generates necessary nats rules for input networks
+// MapNats : Generates necessary nats rules for input networks. A nat gateway
+// on a public network spanning multiple availability zones is mapped to one
+// nat per zone, routing the subnets in the same zone
 func MapNats(d definition.Definition) []output.Nat {
 	var nats []output.Nat
 
 	for _, ng := range d.NatGateways {
-		nws := mapNetworkNames(d, ng.Name)
-		name := d.GeneratedName() + ng.Name
-
-		nats = append(nats, output.Nat{
-			Name:                name,
-			PublicNetwork:       d.GeneratedName() + ng.PublicNetwork,
-			RoutedNetworks:      nws,
-			PublicNetworkAWSID:  `$(networks.items.#[name="` + d.GeneratedName() + ng.PublicNetwork + `"].network_aws_id)`,
-			RoutedNetworkAWSIDs: mapNatNetworkIDs(nws),
-			ProviderType:        "$(datacenters.items.0.type)",
-			DatacenterType:      "$(datacenters.items.0.type)",
-			DatacenterName:      "$(datacenters.items.0.name)",
-			SecretAccessKey:     "$(datacenters.items.0.aws_secret_access_key)",
-			AccessKeyID:         "$(datacenters.items.0.aws_access_key_id)",
-			DatacenterRegion:    "$(datacenters.items.0.region)",
-			VpcID:               "$(vpcs.items.0.vpc_id)",
-		})
+		publics := []definition.Network{{Name: ng.PublicNetwork}}
+		if pn := d.FindNetwork(ng.PublicNetwork); pn != nil {
+			publics = pn.Subnets()
+		}
+
+		routes := mapNatRoutes(d, ng.Name, publics)
+
+		for x, pn := range publics {
+			name := d.GeneratedName() + ng.Name
+			if len(publics) > 1 {
+				name = name + definition.ZoneSuffix(pn.AvailabilityZone)
+			}
+
+			if x > 0 && len(routes[x]) < 1 {
+				continue
+			}
+
+			nats = append(nats, output.Nat{
+				Name:                name,
+				PublicNetwork:       d.GeneratedName() + pn.Name,
+				RoutedNetworks:      routes[x],
+				PublicNetworkAWSID:  `$(networks.items.#[name="` + d.GeneratedName() + pn.Name + `"].network_aws_id)`,
+				RoutedNetworkAWSIDs: mapNatNetworkIDs(routes[x]),
+				ProviderType:        "$(datacenters.items.0.type)",
+				DatacenterType:      "$(datacenters.items.0.type)",
+				DatacenterName:      "$(datacenters.items.0.name)",
+				SecretAccessKey:     "$(datacenters.items.0.aws_secret_access_key)",
+				AccessKeyID:         "$(datacenters.items.0.aws_access_key_id)",
+				DatacenterRegion:    "$(datacenters.items.0.region)",
+				VpcID:               "$(vpcs.items.0.vpc_id)",
+			})
+		}
 	}
 
 	return nats
@@ -43,10 +62,23 @@ func MapDefinitionNats(m *output.FSMMessage) []definition.NatGateway {
 	prefix := m.Datacenters.Items[0].Name + "-" + m.ServiceName + "-"
 
 	for i := len(m.Nats.Items) - 1; i >= 0; i-- {
-		nts = append(nts, definition.NatGateway{
+		ng := definition.NatGateway{
 			Name:          ShortName(m.Nats.Items[i].Name, prefix),
 			PublicNetwork: ShortName(m.Nats.Items[i].PublicNetwork, prefix),
-		})
+		}
+
+		// collapse the nats of a public network spanning multiple availability zones
+		pn := m.FindNetwork(m.Nats.Items[i].PublicNetwork)
+		if pn != nil && pn.Tags["ernest.network"] != "" {
+			ng.Name = strings.TrimSuffix(ng.Name, definition.ZoneSuffix(pn.AvailabilityZone))
+			ng.PublicNetwork = pn.Tags["ernest.network"]
+
+			if hasNatGateway(nts, ng.Name) {
+				continue
+			}
+		}
+
+		nts = append(nts, ng)
 	}
 
 	return nts
@@ -71,6 +103,11 @@ func UpdateNatValues(m *output.FSMMessage) {
 		nwtags := nw.GetTags()
 
 		m.Nats.Items[i].Name = prefix + nwtags["ernest.nat_gateway"]
+
+		if pn.GetTags()["ernest.network"] != "" {
+			m.Nats.Items[i].Name = m.Nats.Items[i].Name + definition.ZoneSuffix(pn.(output.Network).AvailabilityZone)
+		}
+
 		m.Nats.Items[i].PublicNetwork = pn.ComponentName()
 		m.Nats.Items[i].RoutedNetworks = ComponentNamesFromIDs(m.Networks.Items, m.Nats.Items[i].RoutedNetworkAWSIDs)
 		m.Nats.Items[i].ProviderType = "$(datacenters.items.0.type)"
@@ -93,13 +130,39 @@ func mapNatNetworkIDs(nws []string) []string {
 	return ids
 }
 
-func mapNetworkNames(d definition.Definition, name string) []string {
-	var nws []string
+// mapNatRoutes returns the generated names of the subnets routed through
+// each of the public subnets of a nat gateway. Subnets are routed through
+// the public subnet in the same availability zone. A public network in a
+// single zone routes every subnet, while validation rejects subnets in a
+// zone a multi zone public network doesn't use
+func mapNatRoutes(d definition.Definition, name string, publics []definition.Network) [][]string {
+	routes := make([][]string, len(publics))
+
 	for _, network := range d.Networks {
-		if network.NatGateway == name {
-			nws = append(nws, d.GeneratedName()+network.Name)
+		if network.NatGateway != name {
+			continue
+		}
+
+		for _, s := range network.Subnets() {
+			x := 0
+			for i, pn := range publics {
+				if len(publics) > 1 && pn.AvailabilityZone == s.AvailabilityZone {
+					x = i
+				}
+			}
+
+			routes[x] = append(routes[x], d.GeneratedName()+s.Name)
 		}
 	}
 
-	return nws
+	return routes
+}
+
+func hasNatGateway(nts []definition.NatGateway, name string) bool {
+	for _, ng := range nts {
+		if ng.Name == name {
+			return true
+		}
+	}
+	return false
 }
@@ -52,6 +52,59 @@ func TestMapNats(t *testing.T) {
 		})
 	})
 
+	Convey("Given a nat gateway on a network across multiple availability zones", t, func() {
+		d := definition.Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+		}
+
+		d.Networks = append(d.Networks, definition.Network{
+			Name:              "public",
+			Subnet:            "10.0.0.0/24",
+			Public:            true,
+			AvailabilityZones: []string{"eu-west-1a", "eu-west-1b"},
+		})
+
+		d.Networks = append(d.Networks, definition.Network{
+			Name:              "routed",
+			Subnet:            "10.0.1.0/24",
+			NatGateway:        "test",
+			AvailabilityZones: []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"},
+		})
+
+		d.NatGateways = append(d.NatGateways, definition.NatGateway{
+			Name:          "test",
+			PublicNetwork: "public",
+		})
+
+		Convey("When i try to map nats", func() {
+			n := MapNats(d)
+			Convey("Then it should map a nat for each availability zone", func() {
+				So(len(n), ShouldEqual, 2)
+				So(n[0].Name, ShouldEqual, "datacenter-service-test-a")
+				So(n[0].PublicNetwork, ShouldEqual, "datacenter-service-public-a")
+				So(n[0].RoutedNetworks, ShouldResemble, []string{"datacenter-service-routed-a", "datacenter-service-routed-c"})
+				So(n[1].Name, ShouldEqual, "datacenter-service-test-b")
+				So(n[1].PublicNetwork, ShouldEqual, "datacenter-service-public-b")
+				So(n[1].RoutedNetworks, ShouldResemble, []string{"datacenter-service-routed-b"})
+			})
+
+			Convey("And I map them back to definition nat gateways", func() {
+				m := output.FSMMessage{ServiceName: "service"}
+				m.Datacenters.Items = append(m.Datacenters.Items, output.Datacenter{Name: "datacenter"})
+				m.Networks.Items = MapNetworks(d)
+				m.Nats.Items = n
+
+				nts := MapDefinitionNats(&m)
+				Convey("Then they should be grouped into the original nat gateway", func() {
+					So(len(nts), ShouldEqual, 1)
+					So(nts[0].Name, ShouldEqual, "test")
+					So(nts[0].PublicNetwork, ShouldEqual, "public")
+				})
+			})
+		})
+	})
+
 	Convey("Given a valid output message", t, func() {
 		m := output.FSMMessage{
 			ServiceName: "service",
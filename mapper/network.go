@@ -5,6 +5,8 @@
 package mapper
 
 import (
+	"net"
+
 	"github.com/ernestio/aws-definition-mapper/definition"
 	"github.com/ernestio/aws-definition-mapper/output"
 )
@@ -14,36 +16,95 @@ func MapNetworks(d definition.Definition) []output.Network {
 	var networks []output.Network
 
 	for _, network := range d.Networks {
-		name := d.GeneratedName() + network.Name
-
-		n := output.Network{
-			ProviderType:     "$(datacenters.items.0.type)",
-			Name:             name,
-			Subnet:           network.Subnet,
-			IsPublic:         network.Public,
-			AvailabilityZone: network.AvailabilityZone,
-			Tags:             mapNetworkTags(name, d.Name, network.NatGateway),
-			DatacenterType:   "$(datacenters.items.0.type)",
-			DatacenterName:   "$(datacenters.items.0.name)",
-			SecretAccessKey:  "$(datacenters.items.0.aws_secret_access_key)",
-			AccessKeyID:      "$(datacenters.items.0.aws_access_key_id)",
-			DatacenterRegion: "$(datacenters.items.0.region)",
-			VpcID:            "$(vpcs.items.0.vpc_id)",
+		for _, subnet := range network.Subnets() {
+			name := d.GeneratedName() + subnet.Name
+
+			n := output.Network{
+				ProviderType:     "$(datacenters.items.0.type)",
+				Name:             name,
+				Subnet:           subnet.Subnet,
+				IsPublic:         subnet.Public,
+				AvailabilityZone: subnet.AvailabilityZone,
+				Tags:             mapNetworkTags(name, d.Name, subnet.NatGateway),
+				DatacenterType:   "$(datacenters.items.0.type)",
+				DatacenterName:   "$(datacenters.items.0.name)",
+				SecretAccessKey:  "$(datacenters.items.0.aws_secret_access_key)",
+				AccessKeyID:      "$(datacenters.items.0.aws_access_key_id)",
+				DatacenterRegion: "$(datacenters.items.0.region)",
+				VpcID:            "$(vpcs.items.0.vpc_id)",
+			}
+
+			// subnets of a network spanning multiple availability zones
+			// are tagged with the network they were expanded from
+			if len(network.AvailabilityZones) > 0 {
+				n.Tags["ernest.network"] = network.Name
+			}
+
+			networks = append(networks, n)
 		}
-
-		networks = append(networks, n)
 	}
 
 	return networks
 }
 
+// AllocateSubnets assigns subnets to networks that only specify a size,
+// keeping any subnets they were allocated in a previous mapping. The ips
+// of previous instances are recorded, so instances keep their address too
+func AllocateSubnets(d *definition.Definition, om *output.FSMMessage) {
+	previous := make(map[string]string)
+	subnets := make(map[string][]string)
+
+	d.PreviousIPs = make(map[string]net.IP)
+	for _, i := range om.Instances.Items {
+		if i.IP != nil {
+			d.PreviousIPs[ShortName(i.Name, d.GeneratedName())] = i.IP
+		}
+	}
+
+	for _, n := range om.Networks.Items {
+		if n.Tags["ernest.network"] != "" {
+			subnets[n.Tags["ernest.network"]] = append(subnets[n.Tags["ernest.network"]], n.Subnet)
+			continue
+		}
+		previous[ShortName(n.Name, d.GeneratedName())] = n.Subnet
+	}
+
+	for name, s := range subnets {
+		previous[name] = definition.JoinSubnets(s)
+	}
+
+	d.AllocateSubnets(previous)
+}
+
 // MapDefinitionNetworks : Maps output networks into a definition defined networks
 func MapDefinitionNetworks(m *output.FSMMessage) []definition.Network {
 	var nws []definition.Network
 
 	prefix := m.Datacenters.Items[0].Name + "-" + m.ServiceName + "-"
 
+	subnets := make(map[string][]string)
+
 	for _, n := range m.Networks.Items {
+		// regroup the subnets of a network spanning multiple availability zones
+		if group := n.Tags["ernest.network"]; group != "" {
+			if subnets[group] == nil {
+				nws = append(nws, definition.Network{
+					Name:       group,
+					Public:     n.IsPublic,
+					NatGateway: n.Tags["ernest.nat_gateway"],
+				})
+			}
+
+			for i := range nws {
+				if nws[i].Name == group {
+					nws[i].AvailabilityZones = append(nws[i].AvailabilityZones, n.AvailabilityZone)
+				}
+			}
+
+			subnets[group] = append(subnets[group], n.Subnet)
+			continue
+		}
+
 		nws = append(nws, definition.Network{
 			Name:             ShortName(n.Name, prefix),
 			Subnet:           n.Subnet,
@@ -53,6 +114,12 @@ func MapDefinitionNetworks(m *output.FSMMessage) []definition.Network {
 		})
 	}
 
+	for i := range nws {
+		if s, ok := subnets[nws[i].Name]; ok {
+			nws[i].Subnet = definition.JoinSubnets(s)
+		}
+	}
+
 	return nws
 }
 
@@ -81,3 +148,48 @@ func mapNetworkTags(name, service, gateway string) map[string]string {
 
 	return tags
 }
+
+// mapSubnetNames returns the generated names of the subnets that the named
+// networks are expanded into
+func mapSubnetNames(d definition.Definition, names []string) []string {
+	var subnets []string
+
+	for _, name := range names {
+		n := d.FindNetwork(name)
+		if n == nil {
+			subnets = append(subnets, d.GeneratedName()+name)
+			continue
+		}
+
+		for _, s := range n.Subnets() {
+			subnets = append(subnets, d.GeneratedName()+s.Name)
+		}
+	}
+
+	return subnets
+}
+
+// mapInstanceNetwork returns the generated name of the subnet an instance
+// is placed in, spreading instances across all subnets of its network
+func mapInstanceNetwork(d definition.Definition, network string, i int) string {
+	subnets := mapSubnetNames(d, []string{network})
+
+	return subnets[i%len(subnets)]
+}
+
+// mapDefinitionNetworkNames returns the network names of the given network
+// id's, collapsing the subnets of a network spanning multiple availability
+// zones into the network they were expanded from
+func mapDefinitionNetworkNames(m *output.FSMMessage, ids []string, prefix string) []string {
+	var names []string
+
+	for _, c := range ComponentsByIDs(m.Networks.Items, ids) {
+		if group := c.GetTags()["ernest.network"]; group != "" {
+			names = appendStringUnique(names, group)
+			continue
+		}
+		names = appendStringUnique(names, ShortName(c.ComponentName(), prefix))
+	}
+
+	return names
+}
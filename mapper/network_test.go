@@ -39,6 +39,87 @@ func TestNetworksMapping(t *testing.T) {
 
 	})
 
+	Convey("Given a definition with a sized network", t, func() {
+		d := definition.Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+			VpcSubnet:  "10.0.0.0/16",
+		}
+
+		d.Networks = append(d.Networks, definition.Network{
+			Name: "bar",
+			Size: 24,
+		})
+
+		Convey("When I allocate subnets against a previous mapping", func() {
+			var om output.FSMMessage
+			om.Networks.Items = append(om.Networks.Items, output.Network{
+				Name:   "datacenter-service-bar",
+				Subnet: "10.0.5.0/24",
+			})
+
+			AllocateSubnets(&d, &om)
+			n := MapNetworks(d)
+			Convey("Then it should keep the previously allocated subnet", func() {
+				So(n[0].Subnet, ShouldEqual, "10.0.5.0/24")
+			})
+		})
+
+		Convey("When I allocate subnets without a previous mapping", func() {
+			var om output.FSMMessage
+
+			AllocateSubnets(&d, &om)
+			n := MapNetworks(d)
+			Convey("Then it should allocate the first free subnet", func() {
+				So(n[0].Subnet, ShouldEqual, "10.0.0.0/24")
+			})
+		})
+	})
+
+	Convey("Given a definition with a network across multiple availability zones", t, func() {
+		d := definition.Definition{
+			Name:       "service",
+			Datacenter: "datacenter",
+		}
+
+		d.Networks = append(d.Networks, definition.Network{
+			Name:              "bar",
+			Subnet:            "10.0.0.0/24",
+			NatGateway:        "nat",
+			AvailabilityZones: []string{"eu-west-1a", "eu-west-1b"},
+		})
+
+		Convey("When I try to map the network", func() {
+			n := MapNetworks(d)
+			Convey("Then a subnet should be mapped for each availability zone", func() {
+				So(len(n), ShouldEqual, 2)
+				So(n[0].Name, ShouldEqual, "datacenter-service-bar-a")
+				So(n[0].Subnet, ShouldEqual, "10.0.0.0/25")
+				So(n[0].AvailabilityZone, ShouldEqual, "eu-west-1a")
+				So(n[0].Tags["ernest.network"], ShouldEqual, "bar")
+				So(n[0].Tags["ernest.nat_gateway"], ShouldEqual, "nat")
+				So(n[1].Name, ShouldEqual, "datacenter-service-bar-b")
+				So(n[1].Subnet, ShouldEqual, "10.0.0.128/25")
+				So(n[1].AvailabilityZone, ShouldEqual, "eu-west-1b")
+			})
+
+			Convey("And I map them back to definition networks", func() {
+				m := output.FSMMessage{ServiceName: "service"}
+				m.Datacenters.Items = append(m.Datacenters.Items, output.Datacenter{Name: "datacenter"})
+				m.Networks.Items = n
+
+				nws := MapDefinitionNetworks(&m)
+				Convey("Then the subnets should be grouped into the original network", func() {
+					So(len(nws), ShouldEqual, 1)
+					So(nws[0].Name, ShouldEqual, "bar")
+					So(nws[0].Subnet, ShouldEqual, "10.0.0.0/24")
+					So(nws[0].NatGateway, ShouldEqual, "nat")
+					So(nws[0].AvailabilityZones, ShouldResemble, []string{"eu-west-1a", "eu-west-1b"})
+				})
+			})
+		})
+	})
+
 	Convey("Given a valid output message", t, func() {
 		m := output.FSMMessage{
 			ServiceName: "service",
@@ -15,15 +15,12 @@ func MapRDSClusters(d definition.Definition) []output.RDSCluster {
 
 	for _, cluster := range d.RDSClusters {
 		var sgroups []string
-		var networks []string
 
 		for _, sg := range cluster.SecurityGroups {
 			sgroups = append(sgroups, d.GeneratedName()+sg)
 		}
 
-		for _, nw := range cluster.Networks {
-			networks = append(networks, d.GeneratedName()+nw)
-		}
+		networks := mapSubnetNames(d, cluster.Networks)
 
 		name := d.GeneratedName() + cluster.Name
 
@@ -65,7 +62,6 @@ func MapDefinitionRDSClusters(m *output.FSMMessage) []definition.RDSCluster {
 
 	for _, cluster := range m.RDSClusters.Items {
 		sgroups := ComponentNamesFromIDs(m.Firewalls.Items, cluster.SecurityGroupAWSIDs)
-		subnets := ComponentNamesFromIDs(m.Networks.Items, cluster.NetworkAWSIDs)
 
 		c := definition.RDSCluster{
 			Name:              ShortName(cluster.Name, prefix),
@@ -74,7 +70,7 @@ func MapDefinitionRDSClusters(m *output.FSMMessage) []definition.RDSCluster {
 			Port:              cluster.Port,
 			AvailabilityZones: cluster.AvailabilityZones,
 			SecurityGroups:    ShortNames(sgroups, prefix),
-			Networks:          ShortNames(subnets, prefix),
+			Networks:          mapDefinitionNetworkNames(m, cluster.NetworkAWSIDs, prefix),
 			DatabaseName:      cluster.DatabaseName,
 			DatabaseUsername:  cluster.DatabaseUsername,
 			DatabasePassword:  cluster.DatabasePassword,
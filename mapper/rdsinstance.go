@@ -15,15 +15,12 @@ func MapRDSInstances(d definition.Definition) []output.RDSInstance {
 
 	for _, instance := range d.RDSInstances {
 		var sgroups []string
-		var networks []string
 
 		for _, sg := range instance.SecurityGroups {
 			sgroups = append(sgroups, d.GeneratedName()+sg)
 		}
 
-		for _, nw := range instance.Networks {
-			networks = append(networks, d.GeneratedName()+nw)
-		}
+		networks := mapSubnetNames(d, instance.Networks)
 
 		name := d.GeneratedName() + instance.Name
 
@@ -83,7 +80,6 @@ func MapDefinitionRDSInstances(m *output.FSMMessage) []definition.RDSInstance {
 
 	for _, instance := range m.RDSInstances.Items {
 		sgroups := ComponentNamesFromIDs(m.Firewalls.Items, instance.SecurityGroupAWSIDs)
-		subnets := ComponentNamesFromIDs(m.Networks.Items, instance.NetworkAWSIDs)
 
 		i := definition.RDSInstance{
 			Name:              ShortName(instance.Name, prefix),
@@ -97,7 +93,7 @@ func MapDefinitionRDSInstances(m *output.FSMMessage) []definition.RDSInstance {
 			PromotionTier:     instance.PromotionTier,
 			AvailabilityZone:  instance.AvailabilityZone,
 			SecurityGroups:    ShortNames(sgroups, prefix),
-			Networks:          ShortNames(subnets, prefix),
+			Networks:          mapDefinitionNetworkNames(m, instance.NetworkAWSIDs, prefix),
 			DatabaseName:      instance.DatabaseName,
 			DatabaseUsername:  instance.DatabaseUsername,
 			DatabasePassword:  instance.DatabasePassword,
@@ -0,0 +1,213 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ALBHealthCheck ...
+type ALBHealthCheck struct {
+	Path               string `json:"path"`
+	Port               int    `json:"port"`
+	Protocol           string `json:"protocol"`
+	Interval           int    `json:"interval"`
+	Timeout            int    `json:"timeout"`
+	HealthyThreshold   int    `json:"healthy_threshold"`
+	UnhealthyThreshold int    `json:"unhealthy_threshold"`
+	Matcher            string `json:"matcher"`
+}
+
+// ALBTargetGroup ...
+type ALBTargetGroup struct {
+	Name             string           `json:"name"`
+	Port             int              `json:"port"`
+	Protocol         string           `json:"protocol"`
+	HealthCheck      ALBHealthCheck   `json:"health_check"`
+	Instances        []string         `json:"instances"`
+	InstanceNames    sort.StringSlice `json:"instance_names"`
+	InstanceAWSIDs   []string         `json:"instance_aws_ids"`
+	TargetGroupAWSID string           `json:"target_group_aws_id"`
+}
+
+// ALBListenerRule ...
+type ALBListenerRule struct {
+	Path        string `json:"path"`
+	Host        string `json:"host"`
+	Priority    int    `json:"priority"`
+	TargetGroup string `json:"target_group"`
+}
+
+// ALBListener ...
+type ALBListener struct {
+	Port               int               `json:"port"`
+	Protocol           string            `json:"protocol"`
+	SSLCert            string            `json:"ssl_cert"`
+	DefaultTargetGroup string            `json:"default_target_group"`
+	Rules              []ALBListenerRule `json:"rules"`
+}
+
+// ALB : Mapping for an application loadbalancer component
+type ALB struct {
+	Type                string            `json:"_type"`
+	Name                string            `json:"name"`
+	IsPrivate           bool              `json:"is_private"`
+	DNSName             string            `json:"dns_name"`
+	ALBAWSID            string            `json:"alb_aws_id"`
+	Listeners           []ALBListener     `json:"listeners"`
+	TargetGroups        []ALBTargetGroup  `json:"target_groups"`
+	NetworkAWSIDs       []string          `json:"network_aws_ids"`
+	SecurityGroups      sort.StringSlice  `json:"security_groups"`
+	SecurityGroupAWSIDs []string          `json:"security_group_aws_ids"`
+	Tags                map[string]string `json:"tags"`
+	DatacenterType      string            `json:"datacenter_type,omitempty"`
+	DatacenterName      string            `json:"datacenter_name,omitempty"`
+	DatacenterRegion    string            `json:"datacenter_region"`
+	AccessKeyID         string            `json:"aws_access_key_id"`
+	SecretAccessKey     string            `json:"aws_secret_access_key"`
+	VpcID               string            `json:"vpc_id"`
+	Service             string            `json:"service"`
+	Changes             []Change          `json:"changes,omitempty"`
+	Status              string            `json:"status"`
+	Exists              bool
+}
+
+// HasChanged diff's the two items and returns any changes between them
+func (a *ALB) HasChanged(oa *ALB) []Change {
+	var c []Change
+
+	for _, l := range oa.Listeners {
+		if hasALBListener(a.Listeners, l) != true {
+			c = append(c, Change{Field: "listeners", Old: l.String()})
+		}
+	}
+
+	for _, l := range a.Listeners {
+		if hasALBListener(oa.Listeners, l) != true {
+			c = append(c, Change{Field: "listeners", New: l.String()})
+		}
+	}
+
+	for _, tg := range oa.TargetGroups {
+		if a.FindTargetGroup(tg.Name) == nil {
+			c = append(c, Change{Field: "target_groups", Old: tg.String()})
+		}
+	}
+
+	for _, tg := range a.TargetGroups {
+		otg := oa.FindTargetGroup(tg.Name)
+		if otg == nil {
+			c = append(c, Change{Field: "target_groups", New: tg.String()})
+			continue
+		}
+
+		if tg.String() != otg.String() {
+			c = append(c, Change{Field: "target_groups", Old: otg.String(), New: tg.String()})
+		}
+
+		// Sort for comparison
+		tg.InstanceNames.Sort()
+		otg.InstanceNames.Sort()
+
+		c = diffStrings(c, "target_groups."+tg.Name+".instance_names", otg.InstanceNames, tg.InstanceNames)
+	}
+
+	// Sort for comparison
+	a.SecurityGroups.Sort()
+	oa.SecurityGroups.Sort()
+
+	return diffStrings(c, "security_groups", oa.SecurityGroups, a.SecurityGroups)
+}
+
+// FindTargetGroup returns a target group matched by name
+func (a *ALB) FindTargetGroup(name string) *ALBTargetGroup {
+	for i, tg := range a.TargetGroups {
+		if tg.Name == name {
+			return &a.TargetGroups[i]
+		}
+	}
+	return nil
+}
+
+// String returns a human readable representation of the target group
+func (tg ALBTargetGroup) String() string {
+	hc := tg.HealthCheck
+	return fmt.Sprintf("%s %d/%s (health check %s:%d%s %ds/%ds %d/%d %s)", tg.Name, tg.Port, tg.Protocol, hc.Protocol, hc.Port, hc.Path, hc.Interval, hc.Timeout, hc.HealthyThreshold, hc.UnhealthyThreshold, hc.Matcher)
+}
+
+// String returns a human readable representation of the listener
+func (l ALBListener) String() string {
+	s := fmt.Sprintf("%d/%s -> %s", l.Port, l.Protocol, l.DefaultTargetGroup)
+	if l.SSLCert != "" {
+		s = s + " (" + l.SSLCert + ")"
+	}
+	for _, r := range l.Rules {
+		s = s + ", " + r.String()
+	}
+	return s
+}
+
+// String returns a human readable representation of the listener rule
+func (r ALBListenerRule) String() string {
+	return fmt.Sprintf("%d:%s%s -> %s", r.Priority, r.Host, r.Path, r.TargetGroup)
+}
+
+func hasALBListener(listeners []ALBListener, listener ALBListener) bool {
+	for _, l := range listeners {
+		if l.String() == listener.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTags returns a components tags
+func (a ALB) GetTags() map[string]string {
+	return a.Tags
+}
+
+// ProviderID returns a components provider id
+func (a ALB) ProviderID() string {
+	return a.ALBAWSID
+}
+
+// ComponentName returns a components name
+func (a ALB) ComponentName() string {
+	return a.Name
+}
+
+// copyProviderData copies any values generated by aws from a previous alb
+func (a *ALB) copyProviderData(oa *ALB) {
+	a.ALBAWSID = oa.ALBAWSID
+	a.DNSName = oa.DNSName
+	a.Type = "$(datacenters.items.0.type)"
+	a.DatacenterType = "$(datacenters.items.0.type)"
+	a.DatacenterName = "$(datacenters.items.0.name)"
+	a.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	a.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	a.DatacenterRegion = "$(datacenters.items.0.region)"
+	a.VpcID = "$(vpcs.items.0.vpc_id)"
+
+	for x, tg := range a.TargetGroups {
+		otg := oa.FindTargetGroup(tg.Name)
+		if otg != nil {
+			a.TargetGroups[x].TargetGroupAWSID = otg.TargetGroupAWSID
+		}
+	}
+}
+
+// hasInstance returns true if a named instance is registered with any of
+// the alb's target groups
+func (a *ALB) hasInstance(name string) bool {
+	for _, tg := range a.TargetGroups {
+		for _, n := range tg.InstanceNames {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
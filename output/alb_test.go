@@ -0,0 +1,81 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testALB() ALB {
+	return ALB{
+		Name:           "test",
+		SecurityGroups: []string{"web-sg"},
+		TargetGroups: []ALBTargetGroup{
+			ALBTargetGroup{
+				Name:          "test-web",
+				Port:          80,
+				Protocol:      "HTTP",
+				InstanceNames: []string{"web-1", "web-2"},
+				HealthCheck:   ALBHealthCheck{Path: "/health", Protocol: "HTTP"},
+			},
+		},
+		Listeners: []ALBListener{
+			ALBListener{
+				Port:               80,
+				Protocol:           "HTTP",
+				DefaultTargetGroup: "test-web",
+				Rules: []ALBListenerRule{
+					ALBListenerRule{Path: "/api/*", Priority: 1, TargetGroup: "test-web"},
+				},
+			},
+		},
+	}
+}
+
+func TestALBHasChanged(t *testing.T) {
+	Convey("Given an alb", t, func() {
+		a := testALB()
+
+		Convey("When I compare it to an identical alb", func() {
+			oa := testALB()
+			change := a.HasChanged(&oa)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When I compare it to an alb with a different listener rule", func() {
+			oa := testALB()
+			oa.Listeners[0].Rules[0].Path = "/v1/*"
+			change := a.HasChanged(&oa)
+			Convey("Then it should return the changes", func() {
+				So(len(change), ShouldEqual, 2)
+				So(change[0].Old, ShouldEqual, "80/HTTP -> test-web, 1:/v1/* -> test-web")
+				So(change[1].New, ShouldEqual, "80/HTTP -> test-web, 1:/api/* -> test-web")
+			})
+		})
+
+		Convey("When I compare it to an alb with a different health check", func() {
+			oa := testALB()
+			oa.TargetGroups[0].HealthCheck.Path = "/"
+			change := a.HasChanged(&oa)
+			Convey("Then it should return the changes", func() {
+				So(len(change), ShouldEqual, 1)
+				So(change[0].Field, ShouldEqual, "target_groups")
+			})
+		})
+
+		Convey("When I compare it to an alb with different target instances", func() {
+			oa := testALB()
+			oa.TargetGroups[0].InstanceNames = []string{"web-1"}
+			change := a.HasChanged(&oa)
+			Convey("Then it should return the changes", func() {
+				So(change, ShouldResemble, []Change{{Field: "target_groups.test-web.instance_names", New: "web-2"}})
+			})
+		})
+	})
+}
@@ -0,0 +1,211 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ARCSPATHENV : Environment variable pointing to a directory of workflow arc files
+// that should be used instead of the built in definitions
+const ARCSPATHENV = "WORKFLOW_ARCS_PATH"
+
+// ErrUnknownWorkflow : returned when no arcs are defined for a workflow
+var ErrUnknownWorkflow = errors.New("workflow arcs not found")
+
+// hasArcsOverride returns true if the override directory contains the arcs
+// of a workflow
+func hasArcsOverride(name string) bool {
+	dir := os.Getenv(ARCSPATHENV)
+	if dir == "" {
+		return false
+	}
+
+	_, err := os.Stat(filepath.Join(dir, name+"-workflow.json"))
+
+	return err == nil
+}
+
+// LoadArcs returns the arcs definition of a workflow by name (create, delete or import).
+// If the override directory contains a <name>-workflow.json file, it is used instead of the
+// built in definition
+func LoadArcs(name string) ([]byte, error) {
+	if dir := os.Getenv(ARCSPATHENV); dir != "" {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name+"-workflow.json"))
+		if err == nil {
+			return data, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	data, ok := arcs[name]
+	if !ok {
+		return nil, ErrUnknownWorkflow
+	}
+
+	return []byte(data), nil
+}
+
+var arcs = map[string]string{
+	"create": `{
+  "arcs": [
+    { "from": "created", "to": "started",  "event": "service.create" },
+    { "from": "started", "to": "deleting_rds_instances",  "event": "rds_instances.delete" },
+    { "from": "deleting_rds_instances", "to": "rds_instances_deleted",  "event": "rds_instances.delete.done" },
+    { "from": "rds_instances_deleted", "to": "deleting_rds_clusters",  "event": "rds_clusters.delete" },
+    { "from": "deleting_rds_clusters", "to": "rds_clusters_deleted",  "event": "rds_clusters.delete.done" },
+    { "from": "rds_clusters_deleted", "to": "deleting_autoscaling_groups", "event": "autoscaling_groups.delete" },
+    { "from": "deleting_autoscaling_groups", "to": "autoscaling_groups_deleted", "event": "autoscaling_groups.delete.done" },
+    { "from": "autoscaling_groups_deleted", "to": "deleting_elbs", "event": "elbs.delete" },
+    { "from": "deleting_elbs", "to": "elbs_deleted", "event": "elbs.delete.done" },
+    { "from": "elbs_deleted", "to": "deleting_albs", "event": "albs.delete" },
+    { "from": "deleting_albs", "to": "albs_deleted", "event": "albs.delete.done" },
+    { "from": "albs_deleted", "to": "creating_ebs_volumes", "event": "ebs_volumes.create" },
+    { "from": "creating_ebs_volumes", "to": "ebs_volumes_created", "event": "ebs_volumes.create.done" },
+    { "from": "ebs_volumes_created", "to": "updating_ebs_volumes", "event": "ebs_volumes.update" },
+    { "from": "updating_ebs_volumes", "to": "ebs_volumes_updated", "event": "ebs_volumes.update.done" },
+    { "from": "ebs_volumes_updated", "to": "deleting_instances", "event": "instances.delete" },
+    { "from": "deleting_instances", "to": "instances_deleted", "event": "instances.delete.done" },
+    { "from": "instances_deleted", "to": "deleting_nats",  "event": "nats.delete" },
+    { "from": "deleting_nats", "to": "nats_deleted",  "event": "nats.delete.done" },
+    { "from": "nats_deleted", "to": "deleting_networks", "event": "networks.delete" },
+    { "from": "deleting_networks", "to": "networks_deleted", "event": "networks.delete.done" },
+    { "from": "networks_deleted", "to": "deleting_vpcs", "event": "vpcs.delete" },
+    { "from": "deleting_vpcs", "to": "vpcs_deleted", "event": "vpcs.delete.done" },
+    { "from": "vpcs_deleted", "to": "creating_vpcs",  "event": "vpcs.create" },
+    { "from": "creating_vpcs", "to": "vpcs_created",  "event": "vpcs.create.done" },
+    { "from": "vpcs_created", "to": "creating_networks",  "event": "networks.create" },
+    { "from": "creating_networks", "to": "networks_created",  "event": "networks.create.done" },
+    { "from": "networks_created", "to": "updating_networks",  "event": "networks.update" },
+    { "from": "updating_networks", "to": "networks_updated",  "event": "networks.update.done" },
+    { "from": "networks_updated", "to": "creating_firewalls",  "event": "firewalls.create" },
+    { "from": "creating_firewalls", "to": "firewalls_created",  "event": "firewalls.create.done" },
+    { "from": "firewalls_created", "to": "updating_firewalls",  "event": "firewalls.update" },
+    { "from": "updating_firewalls", "to": "firewalls_updated",  "event": "firewalls.update.done" },
+    { "from": "firewalls_updated", "to": "deleting_firewalls",  "event": "firewalls.delete" },
+    { "from": "deleting_firewalls", "to": "firewalls_deleted",  "event": "firewalls.delete.done" },
+    { "from": "firewalls_deleted", "to": "creating_iam_roles",  "event": "iam_roles.create" },
+    { "from": "creating_iam_roles", "to": "iam_roles_created",  "event": "iam_roles.create.done" },
+    { "from": "iam_roles_created", "to": "updating_iam_roles",  "event": "iam_roles.update" },
+    { "from": "updating_iam_roles", "to": "iam_roles_updated",  "event": "iam_roles.update.done" },
+    { "from": "iam_roles_updated", "to": "creating_rds_clusters",  "event": "rds_clusters.create" },
+    { "from": "creating_rds_clusters", "to": "rds_clusters_created",  "event": "rds_clusters.create.done" },
+    { "from": "rds_clusters_created", "to": "updating_rds_clusters",  "event": "rds_clusters.update" },
+    { "from": "updating_rds_clusters", "to": "rds_clusters_updated",  "event": "rds_clusters.update.done" },
+    { "from": "rds_clusters_updated", "to": "creating_rds_instances",  "event": "rds_instances.create" },
+    { "from": "creating_rds_instances", "to": "rds_instances_created",  "event": "rds_instances.create.done" },
+    { "from": "rds_instances_created", "to": "updating_rds_instances",  "event": "rds_instances.update" },
+    { "from": "updating_rds_instances", "to": "rds_instances_updated",  "event": "rds_instances.update.done" },
+    { "from": "rds_instances_updated", "to": "creating_instances",  "event": "instances.create" },
+    { "from": "creating_instances", "to": "instances_created",  "event": "instances.create.done" },
+    { "from": "instances_created", "to": "updating_instances",  "event": "instances.update" },
+    { "from": "updating_instances", "to": "instances_updated",  "event": "instances.update.done" },
+    { "from": "instances_updated", "to": "creating_elbs",  "event": "elbs.create" },
+    { "from": "creating_elbs", "to": "elbs_created",  "event": "elbs.create.done" },
+    { "from": "elbs_created", "to": "updating_elbs",  "event": "elbs.update" },
+    { "from": "updating_elbs", "to": "elbs_updated",  "event": "elbs.update.done" },
+    { "from": "elbs_updated", "to": "creating_albs",  "event": "albs.create" },
+    { "from": "creating_albs", "to": "albs_created",  "event": "albs.create.done" },
+    { "from": "albs_created", "to": "updating_albs",  "event": "albs.update" },
+    { "from": "updating_albs", "to": "albs_updated",  "event": "albs.update.done" },
+    { "from": "albs_updated", "to": "creating_autoscaling_groups",  "event": "autoscaling_groups.create" },
+    { "from": "creating_autoscaling_groups", "to": "autoscaling_groups_created",  "event": "autoscaling_groups.create.done" },
+    { "from": "autoscaling_groups_created", "to": "updating_autoscaling_groups",  "event": "autoscaling_groups.update" },
+    { "from": "updating_autoscaling_groups", "to": "autoscaling_groups_updated",  "event": "autoscaling_groups.update.done" },
+    { "from": "autoscaling_groups_updated", "to": "creating_nats",  "event": "nats.create" },
+    { "from": "creating_nats", "to": "nats_created",  "event": "nats.create.done" },
+    { "from": "nats_created", "to": "updating_nats", "event": "nats.update"},
+    { "from": "updating_nats", "to": "nats_updated",  "event": "nats.update.done" },
+    { "from": "nats_updated", "to": "creating_s3s", "event": "s3s.create"},
+    { "from": "creating_s3s", "to": "s3s_created", "event": "s3s.create.done"},
+    { "from": "s3s_created", "to": "updating_s3s", "event": "s3s.update"},
+    { "from": "updating_s3s", "to": "s3s_updated", "event": "s3s.update.done"},
+    { "from": "s3s_updated", "to": "deleting_s3s", "event": "s3s.delete"},
+    { "from": "deleting_s3s", "to": "s3s_deleted", "event": "s3s.delete.done"},
+    { "from": "s3s_deleted", "to": "deleting_ebs_volumes", "event": "ebs_volumes.delete" },
+    { "from": "deleting_ebs_volumes", "to": "ebs_volumes_deleted", "event": "ebs_volumes.delete.done" },
+    { "from": "ebs_volumes_deleted", "to": "deleting_iam_roles", "event": "iam_roles.delete" },
+    { "from": "deleting_iam_roles", "to": "iam_roles_deleted", "event": "iam_roles.delete.done" },
+    { "from": "iam_roles_deleted", "to": "creating_route53s", "event": "route53s.create"},
+    { "from": "creating_route53s", "to": "route53s_created", "event": "route53s.create.done"},
+    { "from": "route53s_created", "to": "updating_route53s", "event": "route53s.update"},
+    { "from": "updating_route53s", "to": "route53s_updated", "event": "route53s.update.done"},
+    { "from": "route53s_updated", "to": "deleting_route53s", "event": "route53s.delete"},
+    { "from": "deleting_route53s", "to": "route53s_deleted", "event": "route53s.delete.done"},
+    { "from": "route53s_deleted", "to": "done", "event": "service.create.done"},
+    { "from": "pre-failed", "to": "failed", "event": "to_error"},
+    { "from": "failed", "to": "errored", "event": "service.create.error"}
+  ]
+}`,
+	"delete": `{
+  "arcs": [
+    { "from": "created", "to": "started",  "event": "service.delete" },
+    { "from": "started", "to": "deleting_rds_instances", "event": "rds_instances.delete"},
+    { "from": "deleting_rds_instances", "to": "rds_instances_deleted", "event": "rds_instances.delete.done"},
+    { "from": "rds_instances_deleted", "to": "deleting_rds_clusters", "event": "rds_clusters.delete"},
+    { "from": "deleting_rds_clusters", "to": "rds_clusters_deleted", "event": "rds_clusters.delete.done"},
+    { "from": "rds_clusters_deleted", "to": "deleting_autoscaling_groups",  "event": "autoscaling_groups.delete" },
+    { "from": "deleting_autoscaling_groups", "to": "autoscaling_groups_deleted",  "event": "autoscaling_groups.delete.done" },
+    { "from": "autoscaling_groups_deleted", "to": "deleting_elbs",  "event": "elbs.delete" },
+    { "from": "deleting_elbs", "to": "elbs_deleted",  "event": "elbs.delete.done" },
+    { "from": "elbs_deleted", "to": "deleting_albs",  "event": "albs.delete" },
+    { "from": "deleting_albs", "to": "albs_deleted",  "event": "albs.delete.done" },
+    { "from": "albs_deleted", "to": "deleting_nats",  "event": "nats.delete" },
+    { "from": "deleting_nats", "to": "nats_deleted",  "event": "nats.delete.done" },
+    { "from": "nats_deleted", "to": "deleting_instances",  "event": "instances.delete" },
+    { "from": "deleting_instances", "to": "instances_deleted",  "event": "instances.delete.done" },
+    { "from": "instances_deleted", "to": "deleting_ebs_volumes", "event": "ebs_volumes.delete" },
+    { "from": "deleting_ebs_volumes", "to": "ebs_volumes_deleted", "event": "ebs_volumes.delete.done" },
+    { "from": "ebs_volumes_deleted", "to": "deleting_iam_roles", "event": "iam_roles.delete" },
+    { "from": "deleting_iam_roles", "to": "iam_roles_deleted", "event": "iam_roles.delete.done" },
+    { "from": "iam_roles_deleted", "to": "deleting_networks",  "event": "networks.delete" },
+    { "from": "deleting_networks", "to": "networks_deleted",  "event": "networks.delete.done" },
+    { "from": "networks_deleted", "to": "deleting_firewalls",  "event": "firewalls.delete" },
+    { "from": "deleting_firewalls", "to": "firewalls_deleted",  "event": "firewalls.delete.done" },
+    { "from": "firewalls_deleted", "to": "deleting_s3s", "event": "s3s.delete"},
+    { "from": "deleting_s3s", "to": "s3s_deleted", "event": "s3s.delete.done"},
+    { "from": "s3s_deleted", "to": "deleting_route53s", "event": "route53s.delete"},
+    { "from": "deleting_route53s", "to": "route53s_deleted", "event": "route53s.delete.done"},
+    { "from": "route53s_deleted", "to": "done", "event": "service.delete.done"},
+    { "from": "pre-failed", "to": "failed", "event": "to_error"},
+    { "from": "failed", "to": "errored", "event": "service.delete.error"}
+  ]
+}`,
+	"import": `{
+  "arcs": [
+    { "from": "created", "to": "started",  "event": "service.import" },
+    { "from": "started", "to": "importing_vpcs", "event": "vpcs.find"},
+    { "from": "importing_vpcs", "to": "vpcs_imported", "event": "vpcs.find.done"},
+    { "from": "vpcs_imported", "to": "importing_rds_instances", "event": "rds_instances.find"},
+    { "from": "importing_rds_instances", "to": "rds_instances_imported", "event": "rds_instances.find.done"},
+    { "from": "rds_instances_imported", "to": "importing_rds_clusters", "event": "rds_clusters.find"},
+    { "from": "importing_rds_clusters", "to": "rds_clusters_imported", "event": "rds_clusters.find.done"},
+    { "from": "rds_clusters_imported", "to": "importing_elbs",  "event": "elbs.find" },
+    { "from": "importing_elbs", "to": "elbs_imported",  "event": "elbs.find.done" },
+    { "from": "elbs_imported", "to": "importing_nats",  "event": "nats.find" },
+    { "from": "importing_nats", "to": "nats_imported",  "event": "nats.find.done" },
+    { "from": "nats_imported", "to": "importing_instances",  "event": "instances.find" },
+    { "from": "importing_instances", "to": "instances_imported",  "event": "instances.find.done" },
+    { "from": "instances_imported", "to": "importing_ebs_volumes", "event": "ebs_volumes.find" },
+    { "from": "importing_ebs_volumes", "to": "ebs_volumes_imported", "event": "ebs_volumes.find.done" },
+    { "from": "ebs_volumes_imported", "to": "importing_networks",  "event": "networks.find" },
+    { "from": "importing_networks", "to": "networks_imported",  "event": "networks.find.done" },
+    { "from": "networks_imported", "to": "importing_firewalls",  "event": "firewalls.find" },
+    { "from": "importing_firewalls", "to": "firewalls_imported",  "event": "firewalls.find.done" },
+    { "from": "firewalls_imported", "to": "importing_s3s", "event": "s3s.find"},
+    { "from": "importing_s3s", "to": "s3s_imported", "event": "s3s.find.done"},
+    { "from": "s3s_imported", "to": "importing_route53s", "event": "route53s.find"},
+    { "from": "importing_route53s", "to": "route53s_imported", "event": "route53s.find.done"},
+    { "from": "route53s_imported", "to": "done", "event": "service.import.aws.done"},
+    { "from": "pre-failed", "to": "failed", "event": "to_error"},
+    { "from": "failed", "to": "errored", "event": "service.import.error"}
+  ]
+}`,
+}
@@ -0,0 +1,94 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLoadArcs(t *testing.T) {
+	Convey("Given the built in workflows", t, func() {
+		os.Setenv(ARCSPATHENV, "")
+
+		Convey("When I load a known workflow", func() {
+			data, err := LoadArcs("import")
+
+			Convey("Then it should return its arcs", func() {
+				So(err, ShouldBeNil)
+				So(string(data), ShouldContainSubstring, `"event": "service.import"`)
+			})
+		})
+
+		Convey("When I load the create workflow", func() {
+			data, err := LoadArcs("create")
+
+			Convey("Then it should contain every workflow step", func() {
+				So(err, ShouldBeNil)
+				for _, step := range workflowSteps {
+					So(string(data), ShouldContainSubstring, `"event": "`+step+`"`)
+				}
+			})
+		})
+
+		Convey("When I load an unknown workflow", func() {
+			_, err := LoadArcs("unknown")
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldEqual, ErrUnknownWorkflow)
+			})
+		})
+	})
+
+	Convey("Given an override directory", t, func() {
+		dir, _ := ioutil.TempDir("", "arcs")
+		defer os.RemoveAll(dir)
+		defer os.Setenv(ARCSPATHENV, "")
+
+		_ = ioutil.WriteFile(filepath.Join(dir, "delete-workflow.json"), []byte(`{"arcs": []}`), 0644)
+		os.Setenv(ARCSPATHENV, dir)
+
+		Convey("When I load a workflow that is overridden", func() {
+			data, err := LoadArcs("delete")
+
+			Convey("Then it should return the arcs from the directory", func() {
+				So(err, ShouldBeNil)
+				So(string(data), ShouldEqual, `{"arcs": []}`)
+			})
+		})
+
+		Convey("When I generate a workflow that is overridden", func() {
+			_ = ioutil.WriteFile(filepath.Join(dir, "create-workflow.json"), []byte(`{"arcs": [
+				{ "from": "created", "to": "started", "event": "service.create" },
+				{ "from": "started", "to": "creating_instances", "event": "instances.create" },
+				{ "from": "creating_instances", "to": "instances_created", "event": "instances.create.done" },
+				{ "from": "instances_created", "to": "done", "event": "service.create.done" }
+			]}`), 0644)
+
+			var m FSMMessage
+			m.InstancesToCreate.Items = []Instance{{Name: "web-1"}}
+			err := m.GenerateWorkflow("create")
+
+			Convey("Then it should use the overridden arcs instead of generating them", func() {
+				So(err, ShouldBeNil)
+				So(len(m.Workflow.Arcs), ShouldEqual, 4)
+				So(m.Workflow.Arcs[0].Event, ShouldEqual, "service.create")
+			})
+		})
+
+		Convey("When I load a workflow that is not overridden", func() {
+			data, err := LoadArcs("create")
+
+			Convey("Then it should return the built in arcs", func() {
+				So(err, ShouldBeNil)
+				So(string(data), ShouldContainSubstring, `"event": "service.create"`)
+			})
+		})
+	})
+}
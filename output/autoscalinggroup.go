@@ -0,0 +1,98 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"sort"
+	"strconv"
+)
+
+// LaunchConfiguration ...
+type LaunchConfiguration struct {
+	Image               string           `json:"image"`
+	Type                string           `json:"instance_type"`
+	KeyPair             string           `json:"key_pair"`
+	UserData            string           `json:"user_data"`
+	SecurityGroups      sort.StringSlice `json:"security_groups"`
+	SecurityGroupAWSIDs []string         `json:"security_group_aws_ids"`
+}
+
+// AutoscalingGroup : mapping of an autoscaling group component
+type AutoscalingGroup struct {
+	ProviderType          string              `json:"_type"`
+	AutoscalingGroupAWSID string              `json:"autoscaling_group_aws_id"`
+	Name                  string              `json:"name"`
+	LaunchConfiguration   LaunchConfiguration `json:"launch_configuration"`
+	MinSize               int                 `json:"min_size"`
+	MaxSize               int                 `json:"max_size"`
+	DesiredCapacity       int                 `json:"desired_capacity"`
+	Networks              sort.StringSlice    `json:"networks"`
+	NetworkAWSIDs         []string            `json:"network_aws_ids"`
+	ELBs                  sort.StringSlice    `json:"elbs"`
+	Tags                  map[string]string   `json:"tags"`
+	DatacenterType        string              `json:"datacenter_type,omitempty"`
+	DatacenterName        string              `json:"datacenter_name,omitempty"`
+	DatacenterRegion      string              `json:"datacenter_region"`
+	AccessKeyID           string              `json:"aws_access_key_id"`
+	SecretAccessKey       string              `json:"aws_secret_access_key"`
+	VpcID                 string              `json:"vpc_id"`
+	Service               string              `json:"service"`
+	Changes               []Change            `json:"changes,omitempty"`
+	Status                string              `json:"status"`
+	Exists                bool
+}
+
+// HasChanged diff's the two items and returns any changes between them
+func (a *AutoscalingGroup) HasChanged(oa *AutoscalingGroup) []Change {
+	var c []Change
+
+	c = diffString(c, "launch_configuration.image", oa.LaunchConfiguration.Image, a.LaunchConfiguration.Image)
+	c = diffString(c, "launch_configuration.instance_type", oa.LaunchConfiguration.Type, a.LaunchConfiguration.Type)
+	c = diffString(c, "launch_configuration.key_pair", oa.LaunchConfiguration.KeyPair, a.LaunchConfiguration.KeyPair)
+	c = diffSensitive(c, "launch_configuration.user_data", oa.LaunchConfiguration.UserData, a.LaunchConfiguration.UserData)
+
+	// Sort for comparison
+	a.LaunchConfiguration.SecurityGroups.Sort()
+	oa.LaunchConfiguration.SecurityGroups.Sort()
+	a.Networks.Sort()
+	oa.Networks.Sort()
+	a.ELBs.Sort()
+	oa.ELBs.Sort()
+
+	c = diffStrings(c, "launch_configuration.security_groups", oa.LaunchConfiguration.SecurityGroups, a.LaunchConfiguration.SecurityGroups)
+	c = diffString(c, "min_size", strconv.Itoa(oa.MinSize), strconv.Itoa(a.MinSize))
+	c = diffString(c, "max_size", strconv.Itoa(oa.MaxSize), strconv.Itoa(a.MaxSize))
+	c = diffString(c, "desired_capacity", strconv.Itoa(oa.DesiredCapacity), strconv.Itoa(a.DesiredCapacity))
+	c = diffStrings(c, "networks", oa.Networks, a.Networks)
+
+	return diffStrings(c, "elbs", oa.ELBs, a.ELBs)
+}
+
+// GetTags returns a components tags
+func (a AutoscalingGroup) GetTags() map[string]string {
+	return a.Tags
+}
+
+// ProviderID returns a components provider id
+func (a AutoscalingGroup) ProviderID() string {
+	return a.AutoscalingGroupAWSID
+}
+
+// ComponentName returns a components name
+func (a AutoscalingGroup) ComponentName() string {
+	return a.Name
+}
+
+// copyProviderData copies any values generated by aws from a previous autoscaling group
+func (a *AutoscalingGroup) copyProviderData(oa *AutoscalingGroup) {
+	a.AutoscalingGroupAWSID = oa.AutoscalingGroupAWSID
+	a.ProviderType = "$(datacenters.items.0.type)"
+	a.DatacenterType = "$(datacenters.items.0.type)"
+	a.DatacenterName = "$(datacenters.items.0.name)"
+	a.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	a.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	a.DatacenterRegion = "$(datacenters.items.0.region)"
+	a.VpcID = "$(vpcs.items.0.vpc_id)"
+}
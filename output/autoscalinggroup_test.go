@@ -0,0 +1,73 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testAutoscalingGroup() AutoscalingGroup {
+	return AutoscalingGroup{
+		Name: "test",
+		LaunchConfiguration: LaunchConfiguration{
+			Image:          "ami-000000",
+			Type:           "t2.micro",
+			UserData:       "#!/bin/sh",
+			SecurityGroups: []string{"web-sg", "admin-sg"},
+		},
+		MinSize:         1,
+		MaxSize:         3,
+		DesiredCapacity: 2,
+		Networks:        []string{"web-a", "web-b"},
+		ELBs:            []string{"web-lb"},
+	}
+}
+
+func TestAutoscalingGroupHasChanged(t *testing.T) {
+	Convey("Given an autoscaling group", t, func() {
+		a := testAutoscalingGroup()
+
+		Convey("When I compare it to an identical autoscaling group", func() {
+			oa := testAutoscalingGroup()
+			oa.Networks = []string{"web-b", "web-a"}
+			change := a.HasChanged(&oa)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When I compare it to an autoscaling group with a different capacity", func() {
+			oa := testAutoscalingGroup()
+			oa.DesiredCapacity = 1
+			change := a.HasChanged(&oa)
+			Convey("Then it should return the changes", func() {
+				So(change, ShouldResemble, []Change{{Field: "desired_capacity", Old: "1", New: "2"}})
+			})
+		})
+
+		Convey("When I compare it to an autoscaling group with a different image", func() {
+			oa := testAutoscalingGroup()
+			oa.LaunchConfiguration.Image = "ami-111111"
+			change := a.HasChanged(&oa)
+			Convey("Then it should return the changes", func() {
+				So(len(change), ShouldEqual, 1)
+				So(change[0].Field, ShouldEqual, "launch_configuration.image")
+			})
+		})
+
+		Convey("When I compare it to an autoscaling group with different user data", func() {
+			oa := testAutoscalingGroup()
+			oa.LaunchConfiguration.UserData = "#!/bin/bash"
+			change := a.HasChanged(&oa)
+			Convey("Then it should not expose the user data", func() {
+				So(len(change), ShouldEqual, 1)
+				So(change[0].Field, ShouldEqual, "launch_configuration.user_data")
+				So(change[0].New, ShouldNotContainSubstring, "/bin/sh")
+			})
+		})
+	})
+}
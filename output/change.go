@@ -0,0 +1,159 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/ernestio/aws-definition-mapper/secret"
+)
+
+// SENSITIVE : Value reported in place of any sensitive attribute
+const SENSITIVE = "(sensitive)"
+
+// Change : A single attribute that differs between two builds of a component
+type Change struct {
+	Field   string `json:"field"`
+	Old     string `json:"old,omitempty"`
+	New     string `json:"new,omitempty"`
+	Replace bool   `json:"replace,omitempty"`
+}
+
+// String returns a human readable description of the change
+func (c Change) String() string {
+	var s string
+
+	switch {
+	case c.Old == "":
+		s = c.Field + " " + c.New + " added"
+	case c.New == "":
+		s = c.Field + " " + c.Old + " removed"
+	default:
+		s = c.Field + " " + c.Old + " → " + c.New
+	}
+
+	if c.Replace {
+		s = s + " (forces replacement)"
+	}
+
+	return s
+}
+
+// RequiresReplacement returns true if any of the changes can't be applied
+// to an existing component
+func RequiresReplacement(changes []Change) bool {
+	for _, c := range changes {
+		if c.Replace {
+			return true
+		}
+	}
+	return false
+}
+
+func diffString(c []Change, field, ov, nv string) []Change {
+	if ov == nv {
+		return c
+	}
+	return append(c, Change{Field: field, Old: ov, New: nv})
+}
+
+// diffSensitive reports a change without its values. A redacted value from
+// a stored mapping is unknown, so it is not reported as a change
+func diffSensitive(c []Change, field, ov, nv string) []Change {
+	if ov == nv || ov == secret.REDACTED {
+		return c
+	}
+	return append(c, Change{Field: field, Old: SENSITIVE, New: SENSITIVE})
+}
+
+// diffSensitiveReplace reports a change to a sensitive attribute that can
+// only be applied by replacing the component
+func diffSensitiveReplace(c []Change, field, ov, nv string) []Change {
+	n := len(c)
+	c = diffSensitive(c, field, ov, nv)
+	if len(c) > n {
+		c[n].Replace = true
+	}
+	return c
+}
+
+// diffReplace reports a change that can only be applied by replacing the
+// component
+func diffReplace(c []Change, field, ov, nv string) []Change {
+	if ov == nv {
+		return c
+	}
+	return append(c, Change{Field: field, Old: ov, New: nv, Replace: true})
+}
+
+// diffTags reports every tag that has been added, removed or modified,
+// ignoring any of the given keys
+func diffTags(c []Change, ov, nv map[string]string, ignored ...string) []Change {
+	var keys []string
+
+	for k := range ov {
+		keys = append(keys, k)
+	}
+	for k := range nv {
+		if _, ok := ov[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if hasString(ignored, k) {
+			continue
+		}
+		c = diffString(c, "tags."+k, ov[k], nv[k])
+	}
+
+	return c
+}
+
+func diffBool(c []Change, field string, ov, nv bool) []Change {
+	return diffString(c, field, strconv.FormatBool(ov), strconv.FormatBool(nv))
+}
+
+// diffInt64 reports a change between two optional values. An unset value
+// is reported as an empty value, so setting or unsetting a value is a change
+func diffInt64(c []Change, field string, ov, nv *int64) []Change {
+	return diffString(c, field, formatInt64(ov), formatInt64(nv))
+}
+
+func formatInt64(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+// diffStrings reports every value that has been added to or removed from a list
+func diffStrings(c []Change, field string, ov, nv []string) []Change {
+	for _, v := range ov {
+		if hasString(nv, v) != true {
+			c = append(c, Change{Field: field, Old: v})
+		}
+	}
+
+	for _, v := range nv {
+		if hasString(ov, v) != true {
+			c = append(c, Change{Field: field, New: v})
+		}
+	}
+
+	return c
+}
+
+func hasString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+// Datacenters : A list of datacenters and the status of the step processing them
+type Datacenters struct {
+	Started  string       `json:"started"`
+	Finished string       `json:"finished"`
+	Status   string       `json:"status"`
+	Items    []Datacenter `json:"items"`
+}
+
+// VPCs : A list of vpcs and the status of the step processing them
+type VPCs struct {
+	Started  string `json:"started"`
+	Finished string `json:"finished"`
+	Status   string `json:"status"`
+	Items    []VPC  `json:"items"`
+}
+
+// Networks : A list of networks and the status of the step processing them
+type Networks struct {
+	Started  string    `json:"started"`
+	Finished string    `json:"finished"`
+	Status   string    `json:"status"`
+	Items    []Network `json:"items"`
+}
+
+// Instances : A list of instances and the status of the step processing them
+type Instances struct {
+	Started  string     `json:"started"`
+	Finished string     `json:"finished"`
+	Status   string     `json:"status"`
+	Items    []Instance `json:"items"`
+}
+
+// AutoscalingGroups : A list of autoscaling groups and the status of the step processing them
+type AutoscalingGroups struct {
+	Started  string             `json:"started"`
+	Finished string             `json:"finished"`
+	Status   string             `json:"status"`
+	Items    []AutoscalingGroup `json:"items"`
+}
+
+// IAMRoles : A list of iam roles and the status of the step processing them
+type IAMRoles struct {
+	Started  string    `json:"started"`
+	Finished string    `json:"finished"`
+	Status   string    `json:"status"`
+	Items    []IAMRole `json:"items"`
+}
+
+// Firewalls : A list of firewalls and the status of the step processing them
+type Firewalls struct {
+	Started  string     `json:"started"`
+	Finished string     `json:"finished"`
+	Status   string     `json:"status"`
+	Items    []Firewall `json:"items"`
+}
+
+// Nats : A list of nat gateways and the status of the step processing them
+type Nats struct {
+	Started  string `json:"started"`
+	Finished string `json:"finished"`
+	Status   string `json:"status"`
+	Items    []Nat  `json:"items"`
+}
+
+// ELBs : A list of elbs and the status of the step processing them
+type ELBs struct {
+	Started  string `json:"started"`
+	Finished string `json:"finished"`
+	Status   string `json:"status"`
+	Items    []ELB  `json:"items"`
+}
+
+// ALBs : A list of albs and the status of the step processing them
+type ALBs struct {
+	Started  string `json:"started"`
+	Finished string `json:"finished"`
+	Status   string `json:"status"`
+	Items    []ALB  `json:"items"`
+}
+
+// S3s : A list of s3 buckets and the status of the step processing them
+type S3s struct {
+	Started  string `json:"started"`
+	Finished string `json:"finished"`
+	Status   string `json:"status"`
+	Items    []S3   `json:"items"`
+}
+
+// Route53s : A list of route53 zones and the status of the step processing them
+type Route53s struct {
+	Started  string        `json:"started"`
+	Finished string        `json:"finished"`
+	Status   string        `json:"status"`
+	Items    []Route53Zone `json:"items"`
+}
+
+// RDSClusters : A list of rds clusters and the status of the step processing them
+type RDSClusters struct {
+	Started  string       `json:"started"`
+	Finished string       `json:"finished"`
+	Status   string       `json:"status"`
+	Items    []RDSCluster `json:"items"`
+}
+
+// RDSInstances : A list of rds instances and the status of the step processing them
+type RDSInstances struct {
+	Started  string        `json:"started"`
+	Finished string        `json:"finished"`
+	Status   string        `json:"status"`
+	Items    []RDSInstance `json:"items"`
+}
+
+// EBSVolumes : A list of ebs volumes and the status of the step processing them
+type EBSVolumes struct {
+	Started  string      `json:"started"`
+	Finished string      `json:"finished"`
+	Status   string      `json:"status"`
+	Items    []EBSVolume `json:"items"`
+}
@@ -0,0 +1,78 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+const (
+	// DRIFTMODIFIED : Component has been changed outside of ernest
+	DRIFTMODIFIED = "modified"
+	// DRIFTADDED : Component has been created outside of ernest
+	DRIFTADDED = "added"
+	// DRIFTREMOVED : Component has been deleted outside of ernest
+	DRIFTREMOVED = "removed"
+)
+
+// DriftItem : A single component that differs from the stored mapping
+type DriftItem struct {
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	State   string   `json:"state"`
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// Drift : Components of a service that have been changed outside of ernest
+type Drift struct {
+	ID      string      `json:"id"`
+	Service string      `json:"service"`
+	Name    string      `json:"name"`
+	Items   []DriftItem `json:"items"`
+}
+
+// Drift compares an imported FSMMessage with the stored mapping of the
+// service. Changes are reported from the stored value to the imported one.
+// Component types that are never compared between builds, such as the vpc,
+// are ignored
+func (m *FSMMessage) Drift(om FSMMessage) Drift {
+	d := Drift{
+		ID:      om.ID,
+		Service: om.Service,
+		Name:    om.ServiceName,
+		Items:   []DriftItem{},
+	}
+
+	for _, t := range ComponentTypes {
+		if t.HasChanged == nil {
+			continue
+		}
+
+		for _, o := range om.Components(t.Collection) {
+			c := m.Find(t.Collection, t.Name(o))
+			if c == nil {
+				d.add(t.Type, t.Name(o), DRIFTREMOVED, nil)
+				continue
+			}
+
+			if changes := t.HasChanged(c, o); len(changes) > 0 {
+				d.add(t.Type, t.Name(o), DRIFTMODIFIED, changes)
+			}
+		}
+
+		for _, c := range m.Components(t.Collection) {
+			if om.Find(t.Collection, t.Name(c)) == nil {
+				d.add(t.Type, t.Name(c), DRIFTADDED, nil)
+			}
+		}
+	}
+
+	return d
+}
+
+func (d *Drift) add(ctype, name, state string, changes []Change) {
+	d.Items = append(d.Items, DriftItem{
+		Type:    ctype,
+		Name:    name,
+		State:   state,
+		Changes: changes,
+	})
+}
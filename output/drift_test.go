@@ -0,0 +1,64 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDrift(t *testing.T) {
+	Convey("Given a stored mapping", t, func() {
+		var om FSMMessage
+		om.ID = "service-1"
+		om.VPCs.Items = []VPC{{VpcID: "vpc-1"}}
+		om.Firewalls.Items = []Firewall{{Name: "web"}}
+		om.Firewalls.Items[0].Rules.Ingress = []FirewallRule{{IP: "10.0.0.0/16", From: 80, To: 80, Protocol: "tcp"}}
+		om.Instances.Items = []Instance{
+			{Name: "web-1", Type: "t2.micro", Image: "ami-000000"},
+			{Name: "web-2", Type: "t2.micro", Image: "ami-000000"},
+		}
+
+		Convey("When I compare it to an identical import", func() {
+			var m FSMMessage
+			m.Firewalls.Items = om.Firewalls.Items
+			m.Instances.Items = om.Instances.Items
+
+			d := m.Drift(om)
+
+			Convey("Then it should report no drift", func() {
+				So(d.ID, ShouldEqual, "service-1")
+				So(d.Items, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When I compare it to an import changed outside of ernest", func() {
+			var m FSMMessage
+			m.Firewalls.Items = []Firewall{{Name: "web"}}
+			m.Firewalls.Items[0].Rules.Ingress = []FirewallRule{{IP: "0.0.0.0/0", From: 80, To: 80, Protocol: "tcp"}}
+			m.Instances.Items = []Instance{
+				{Name: "web-1", Type: "t2.micro", Image: "ami-000000"},
+				{Name: "web-3", Type: "t2.micro", Image: "ami-000000"},
+			}
+
+			d := m.Drift(om)
+
+			Convey("Then it should report every modified, added and removed component", func() {
+				So(len(d.Items), ShouldEqual, 3)
+				So(d.Items[0].Type, ShouldEqual, "instance")
+				So(d.Items[0].Name, ShouldEqual, "web-2")
+				So(d.Items[0].State, ShouldEqual, DRIFTREMOVED)
+				So(d.Items[1].Name, ShouldEqual, "web-3")
+				So(d.Items[1].State, ShouldEqual, DRIFTADDED)
+				So(d.Items[2].Type, ShouldEqual, "firewall")
+				So(d.Items[2].State, ShouldEqual, DRIFTMODIFIED)
+				So(len(d.Items[2].Changes), ShouldEqual, 2)
+				So(d.Items[2].Changes[0].Old, ShouldNotBeEmpty)
+				So(d.Items[2].Changes[1].New, ShouldNotBeEmpty)
+			})
+		})
+	})
+}
@@ -22,13 +22,22 @@ type EBSVolume struct {
 	Tags             map[string]string `json:"tags"`
 	Encrypted        bool              `json:"encrypted"`
 	EncryptionKeyID  *string           `json:"encryption_key_id"`
+	Changes          []Change          `json:"changes,omitempty"`
 	Status           string            `json:"status"`
 	Exists           bool
 }
 
-// HasChanged diff's the two items and returns true if there have been any changes
-func (v *EBSVolume) HasChanged(ov *EBSVolume) bool {
-	return false
+// HasChanged diff's the two items and returns any changes between them.
+// The size, type and iops of a volume can be modified while it is in use,
+// but moving it to another availability zone requires a new volume
+func (v *EBSVolume) HasChanged(ov *EBSVolume) []Change {
+	var c []Change
+
+	c = diffReplace(c, "availability_zone", ov.AvailabilityZone, v.AvailabilityZone)
+	c = diffInt64(c, "size", ov.Size, v.Size)
+	c = diffString(c, "volume_type", ov.VolumeType, v.VolumeType)
+
+	return diffInt64(c, "iops", ov.Iops, v.Iops)
 }
 
 // GetTags returns tags
@@ -45,3 +54,13 @@ func (v EBSVolume) ProviderID() string {
 func (v EBSVolume) ComponentName() string {
 	return v.Name
 }
+
+// copyProviderData copies any values generated by aws from a previous ebs volume
+func (v *EBSVolume) copyProviderData(ov *EBSVolume) {
+	v.VolumeAWSID = ov.VolumeAWSID
+	v.DatacenterType = "$(datacenters.items.0.type)"
+	v.DatacenterName = "$(datacenters.items.0.name)"
+	v.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	v.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	v.DatacenterRegion = "$(datacenters.items.0.region)"
+}
@@ -19,17 +19,54 @@ func TestEBSHasChanged(t *testing.T) {
 			Size:             int64p(100),
 		}
 
-		// EBS Volumes are immutable
-		Convey("When I compare it to an changed network", func() {
+		Convey("When I compare it to a volume with a different size, type and iops", func() {
 			ov := EBSVolume{
 				Name:             "test",
 				AvailabilityZone: "eu-west-1",
 				VolumeType:       "io1",
-				Size:             int64p(500),
+				Size:             int64p(50),
+				Iops:             int64p(1000),
 			}
+			v.Iops = int64p(2000)
 			change := v.HasChanged(&ov)
-			Convey("Then it should return false", func() {
-				So(change, ShouldBeFalse)
+			Convey("Then it should return the changes", func() {
+				So(change, ShouldResemble, []Change{
+					{Field: "size", Old: "50", New: "100"},
+					{Field: "volume_type", Old: "io1", New: "gp2"},
+					{Field: "iops", Old: "1000", New: "2000"},
+				})
+			})
+		})
+
+		Convey("When I compare it to a volume without iops", func() {
+			ov := v
+			v.Iops = int64p(1000)
+			change := v.HasChanged(&ov)
+			Convey("Then setting the iops should be a change", func() {
+				So(change, ShouldResemble, []Change{
+					{Field: "iops", Old: "", New: "1000"},
+				})
+			})
+		})
+
+		Convey("When I compare it to a volume with iops", func() {
+			ov := v
+			ov.Iops = int64p(1000)
+			change := v.HasChanged(&ov)
+			Convey("Then unsetting the iops should be a change", func() {
+				So(change, ShouldResemble, []Change{
+					{Field: "iops", Old: "1000", New: ""},
+				})
+			})
+		})
+
+		Convey("When I compare it to a volume in a different availability zone", func() {
+			ov := v
+			ov.AvailabilityZone = "eu-west-2"
+			change := v.HasChanged(&ov)
+			Convey("Then it should require a replacement", func() {
+				So(RequiresReplacement(change), ShouldBeTrue)
+				So(change[0].Field, ShouldEqual, "availability_zone")
 			})
 		})
 
@@ -41,8 +78,8 @@ func TestEBSHasChanged(t *testing.T) {
 				Size:             int64p(100),
 			}
 			change := v.HasChanged(&ov)
-			Convey("Then it should return false", func() {
-				So(change, ShouldBeFalse)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
 			})
 		})
 	})
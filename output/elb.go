@@ -5,7 +5,7 @@
 package output
 
 import (
-	"reflect"
+	"fmt"
 	"sort"
 )
 
@@ -38,22 +38,24 @@ type ELB struct {
 	SecretAccessKey     string            `json:"aws_secret_access_key"`
 	VpcID               string            `json:"vpc_id"`
 	Service             string            `json:"service"`
+	Changes             []Change          `json:"changes,omitempty"`
 	Status              string            `json:"status"`
 	Exists              bool
 }
 
-// HasChanged diff's the two items and returns true if there have been any changes
-func (e *ELB) HasChanged(oe *ELB) bool {
-	if len(e.Listeners) != len(oe.Listeners) {
-		return true
+// HasChanged diff's the two items and returns any changes between them
+func (e *ELB) HasChanged(oe *ELB) []Change {
+	var c []Change
+
+	for _, l := range oe.Listeners {
+		if hasListener(e.Listeners, l) != true {
+			c = append(c, Change{Field: "listeners", Old: l.String()})
+		}
 	}
 
-	for i := 0; i < len(e.Listeners); i++ {
-		if e.Listeners[i].FromPort != oe.Listeners[i].FromPort ||
-			e.Listeners[i].ToPort != oe.Listeners[i].ToPort ||
-			e.Listeners[i].Protocol != oe.Listeners[i].Protocol ||
-			e.Listeners[i].SSLCert != oe.Listeners[i].SSLCert {
-			return true
+	for _, l := range e.Listeners {
+		if hasListener(oe.Listeners, l) != true {
+			c = append(c, Change{Field: "listeners", New: l.String()})
 		}
 	}
 
@@ -63,14 +65,26 @@ func (e *ELB) HasChanged(oe *ELB) bool {
 	e.SecurityGroups.Sort()
 	oe.SecurityGroups.Sort()
 
-	if !reflect.DeepEqual(e.InstanceNames, oe.InstanceNames) {
-		return true
-	}
+	c = diffStrings(c, "instance_names", oe.InstanceNames, e.InstanceNames)
 
-	if !reflect.DeepEqual(e.SecurityGroups, oe.SecurityGroups) {
-		return true
+	return diffStrings(c, "security_groups", oe.SecurityGroups, e.SecurityGroups)
+}
+
+// String returns a human readable representation of the listener
+func (l ELBListener) String() string {
+	s := fmt.Sprintf("%d:%d/%s", l.FromPort, l.ToPort, l.Protocol)
+	if l.SSLCert != "" {
+		s = s + " (" + l.SSLCert + ")"
 	}
+	return s
+}
 
+func hasListener(listeners []ELBListener, listener ELBListener) bool {
+	for _, l := range listeners {
+		if l == listener {
+			return true
+		}
+	}
 	return false
 }
 
@@ -88,3 +102,25 @@ func (e ELB) ProviderID() string {
 func (e ELB) ComponentName() string {
 	return e.Name
 }
+
+// copyProviderData copies any values generated by aws from a previous elb
+func (e *ELB) copyProviderData(oe *ELB) {
+	e.DNSName = oe.DNSName
+	e.Type = "$(datacenters.items.0.type)"
+	e.DatacenterType = "$(datacenters.items.0.type)"
+	e.DatacenterName = "$(datacenters.items.0.name)"
+	e.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	e.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	e.DatacenterRegion = "$(datacenters.items.0.region)"
+	e.VpcID = "$(vpcs.items.0.vpc_id)"
+}
+
+// hasInstance returns true if a named instance is registered with the elb
+func (e *ELB) hasInstance(name string) bool {
+	for _, n := range e.InstanceNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
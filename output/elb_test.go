@@ -49,8 +49,10 @@ func TestELBHasChanged(t *testing.T) {
 				},
 			}
 			change := e.HasChanged(&oe)
-			Convey("Then it should return true", func() {
-				So(change, ShouldBeTrue)
+			Convey("Then it should return the changes", func() {
+				So(len(change), ShouldEqual, 2)
+				So(change[0].Old, ShouldEqual, "1:80/HTTP (cert)")
+				So(change[1].New, ShouldEqual, "1:2/HTTP (cert)")
 			})
 		})
 
@@ -74,8 +76,8 @@ func TestELBHasChanged(t *testing.T) {
 			}
 
 			change := e.HasChanged(&oe)
-			Convey("Then it should return false", func() {
-				So(change, ShouldBeFalse)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
 			})
 		})
 	})
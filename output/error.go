@@ -0,0 +1,55 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import "encoding/json"
+
+const (
+	// ERRINVALIDPAYLOAD : The request payload could not be parsed
+	ERRINVALIDPAYLOAD = "invalid_payload"
+	// ERRVALIDATIONFAILED : The definition is not valid
+	ERRVALIDATIONFAILED = "validation_failed"
+	// ERRPREVIOUSMAPPINGUNAVAILABLE : The mapping of the previous build could not be loaded
+	ERRPREVIOUSMAPPINGUNAVAILABLE = "previous_mapping_unavailable"
+	// ERRVPCIMMUTABLE : The vpc of a service can't change between builds
+	ERRVPCIMMUTABLE = "vpc_immutable"
+	// ERRWORKFLOWGENERATIONFAILED : The workflow could not be generated
+	ERRWORKFLOWGENERATIONFAILED = "workflow_generation_failed"
+	// ERRMARSHALFAILED : The response could not be encoded
+	ERRMARSHALFAILED = "marshal_failed"
+	// ERRSECRETUNRESOLVED : A secret referenced by the definition could not be resolved
+	ERRSECRETUNRESOLVED = "secret_unresolved"
+)
+
+// Error : Error response sent in place of a mapping. The message is kept
+// under "error" so existing consumers can still read it
+type Error struct {
+	Code    string      `json:"code"`
+	Message string      `json:"error"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// NewError returns an error response
+func NewError(code, message string, details interface{}) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+		Details: details,
+	}
+}
+
+// Error returns the error message
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// JSON returns the encoded error response
+func (e *Error) JSON() []byte {
+	data, err := json.Marshal(e)
+	if err != nil {
+		data, _ = json.Marshal(Error{Code: e.Code, Message: e.Message})
+	}
+	return data
+}
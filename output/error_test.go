@@ -0,0 +1,38 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestError(t *testing.T) {
+	Convey("Given an error response", t, func() {
+		e := NewError(ERRVALIDATIONFAILED, `Route53 record type 'X' is not a "valid" dns type`, []string{"records[0].type"})
+
+		Convey("When I encode it", func() {
+			var r map[string]interface{}
+			err := json.Unmarshal(e.JSON(), &r)
+
+			Convey("Then it should be valid json", func() {
+				So(err, ShouldBeNil)
+				So(r["code"], ShouldEqual, "validation_failed")
+				So(r["error"], ShouldEqual, `Route53 record type 'X' is not a "valid" dns type`)
+				So(r["details"], ShouldResemble, []interface{}{"records[0].type"})
+			})
+		})
+
+		Convey("When it has no details", func() {
+			e.Details = nil
+
+			Convey("Then they should be omitted", func() {
+				So(string(e.JSON()), ShouldNotContainSubstring, "details")
+			})
+		})
+	})
+}
@@ -21,30 +21,34 @@ type Firewall struct {
 	SecretAccessKey  string            `json:"aws_secret_access_key"`
 	VpcID            string            `json:"vpc_id"`
 	Service          string            `json:"service"`
+	Changes          []Change          `json:"changes,omitempty"`
 	Status           string            `json:"status"`
 	Exists           bool
 }
 
-// HasChanged diff's the two items and returns true if there have been any changes
-func (f *Firewall) HasChanged(of *Firewall) bool {
-	if len(f.Rules.Ingress) != len(of.Rules.Ingress) ||
-		len(f.Rules.Egress) != len(of.Rules.Egress) {
-		return true
-	}
+// HasChanged diff's the two items and returns any changes between them
+func (f *Firewall) HasChanged(of *Firewall) []Change {
+	var c []Change
 
-	for _, rule := range f.Rules.Ingress {
-		if hasRule(of.Rules.Ingress, rule) != true {
-			return true
+	c = diffRules(c, "ingress rule", of.Rules.Ingress, f.Rules.Ingress)
+
+	return diffRules(c, "egress rule", of.Rules.Egress, f.Rules.Egress)
+}
+
+func diffRules(c []Change, field string, orules, nrules []FirewallRule) []Change {
+	for _, rule := range orules {
+		if hasRule(nrules, rule) != true {
+			c = append(c, Change{Field: field, Old: rule.String()})
 		}
 	}
 
-	for _, rule := range f.Rules.Egress {
-		if hasRule(of.Rules.Egress, rule) != true {
-			return true
+	for _, rule := range nrules {
+		if hasRule(orules, rule) != true {
+			c = append(c, Change{Field: field, New: rule.String()})
 		}
 	}
 
-	return false
+	return c
 }
 
 func hasRule(rules []FirewallRule, rule FirewallRule) bool {
@@ -52,6 +56,7 @@ func hasRule(rules []FirewallRule, rule FirewallRule) bool {
 		if ruleMatches(r.To, rule.To, r.Protocol, rule.Protocol) &&
 			r.Protocol == rule.Protocol &&
 			r.IP == rule.IP &&
+			r.SecurityGroup == rule.SecurityGroup &&
 			ruleMatches(r.From, rule.From, r.Protocol, rule.Protocol) {
 			return true
 		}
@@ -82,3 +87,15 @@ func (f Firewall) ProviderID() string {
 func (f Firewall) ComponentName() string {
 	return f.Name
 }
+
+// copyProviderData copies any values generated by aws from a previous firewall
+func (f *Firewall) copyProviderData(of *Firewall) {
+	f.SecurityGroupAWSID = of.SecurityGroupAWSID
+	f.ProviderType = "$(datacenters.items.0.type)"
+	f.DatacenterType = "$(datacenters.items.0.type)"
+	f.DatacenterName = "$(datacenters.items.0.name)"
+	f.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	f.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	f.DatacenterRegion = "$(datacenters.items.0.region)"
+	f.VpcID = "$(vpcs.items.0.vpc_id)"
+}
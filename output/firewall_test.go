@@ -31,8 +31,10 @@ func TestFirewallHasChanged(t *testing.T) {
 			})
 
 			change := f.HasChanged(&of)
-			Convey("Then it should return true", func() {
-				So(change, ShouldBeTrue)
+			Convey("Then it should return the changes", func() {
+				So(len(change), ShouldEqual, 2)
+				So(change[0].String(), ShouldEqual, "ingress rule 10.10.10.10:80-8080/tcp removed")
+				So(change[1].String(), ShouldEqual, "ingress rule 10.10.10.10:80/tcp added")
 			})
 		})
 
@@ -47,8 +49,35 @@ func TestFirewallHasChanged(t *testing.T) {
 			})
 
 			change := f.HasChanged(&of)
-			Convey("Then it should return false", func() {
-				So(change, ShouldBeFalse)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When I compare it to a firewall with a different source security group", func() {
+			var of Firewall
+
+			f.Rules.Ingress = append(f.Rules.Ingress, FirewallRule{
+				SecurityGroup:      "db-sg",
+				SecurityGroupAWSID: `$(firewalls.items.#[name="db-sg"].security_group_aws_id)`,
+				From:               5432,
+				To:                 5432,
+				Protocol:           "tcp",
+			})
+
+			of.Rules.Ingress = append(of.Rules.Ingress, f.Rules.Ingress[0], FirewallRule{
+				SecurityGroup:      "web-sg",
+				SecurityGroupAWSID: "sg-0000000",
+				From:               5432,
+				To:                 5432,
+				Protocol:           "tcp",
+			})
+
+			change := f.HasChanged(&of)
+			Convey("Then it should return the changes", func() {
+				So(len(change), ShouldEqual, 2)
+				So(change[0].String(), ShouldEqual, "ingress rule web-sg:5432/tcp removed")
+				So(change[1].String(), ShouldEqual, "ingress rule db-sg:5432/tcp added")
 			})
 		})
 	})
@@ -4,10 +4,34 @@
 
 package output
 
+import "strconv"
+
 // FirewallRule ...
 type FirewallRule struct {
-	IP       string `json:"ip"`
-	From     int    `json:"from_port"`
-	To       int    `json:"to_port"`
-	Protocol string `json:"protocol"`
+	IP                 string `json:"ip"`
+	SecurityGroup      string `json:"security_group,omitempty"`
+	SecurityGroupAWSID string `json:"security_group_aws_id,omitempty"`
+	From               int    `json:"from_port"`
+	To                 int    `json:"to_port"`
+	Protocol           string `json:"protocol"`
+}
+
+// String returns a human readable representation of the rule
+func (r FirewallRule) String() string {
+	ports := strconv.Itoa(r.From)
+	if r.From != r.To {
+		ports = ports + "-" + strconv.Itoa(r.To)
+	}
+
+	protocol := r.Protocol
+	if protocol == "-1" {
+		protocol = "any"
+	}
+
+	source := r.IP
+	if r.SecurityGroup != "" {
+		source = r.SecurityGroup
+	}
+
+	return source + ":" + ports + "/" + protocol
 }
@@ -0,0 +1,98 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import "sort"
+
+// IAMPolicy ...
+type IAMPolicy struct {
+	Name     string `json:"name"`
+	Document string `json:"document"`
+}
+
+// IAMRole : mapping of an iam role and its generated instance profile
+type IAMRole struct {
+	ProviderType         string            `json:"_type"`
+	RoleAWSID            string            `json:"role_aws_id"`
+	Name                 string            `json:"name"`
+	AssumeRolePolicy     string            `json:"assume_role_policy"`
+	Policies             []IAMPolicy       `json:"policies"`
+	ManagedPolicies      sort.StringSlice  `json:"managed_policies"`
+	InstanceProfile      string            `json:"instance_profile"`
+	InstanceProfileAWSID string            `json:"instance_profile_aws_id"`
+	Tags                 map[string]string `json:"tags"`
+	DatacenterName       string            `json:"datacenter_name,omitempty"`
+	DatacenterRegion     string            `json:"datacenter_region"`
+	AccessKeyID          string            `json:"aws_access_key_id"`
+	SecretAccessKey      string            `json:"aws_secret_access_key"`
+	Service              string            `json:"service"`
+	Changes              []Change          `json:"changes,omitempty"`
+	Status               string            `json:"status"`
+	Exists               bool
+}
+
+// HasChanged diff's the two items and returns any changes between them
+func (r *IAMRole) HasChanged(or *IAMRole) []Change {
+	var c []Change
+
+	c = diffString(c, "assume_role_policy", or.AssumeRolePolicy, r.AssumeRolePolicy)
+
+	for _, p := range or.Policies {
+		if r.FindPolicy(p.Name) == nil {
+			c = append(c, Change{Field: "policies", Old: p.Name})
+		}
+	}
+
+	for _, p := range r.Policies {
+		op := or.FindPolicy(p.Name)
+		if op == nil {
+			c = append(c, Change{Field: "policies", New: p.Name})
+			continue
+		}
+		c = diffString(c, "policies."+p.Name+".document", op.Document, p.Document)
+	}
+
+	// Sort for comparison
+	r.ManagedPolicies.Sort()
+	or.ManagedPolicies.Sort()
+
+	return diffStrings(c, "managed_policies", or.ManagedPolicies, r.ManagedPolicies)
+}
+
+// FindPolicy returns an inline policy matched by name
+func (r *IAMRole) FindPolicy(name string) *IAMPolicy {
+	for i, p := range r.Policies {
+		if p.Name == name {
+			return &r.Policies[i]
+		}
+	}
+	return nil
+}
+
+// GetTags returns a components tags
+func (r IAMRole) GetTags() map[string]string {
+	return r.Tags
+}
+
+// ProviderID returns a components provider id
+func (r IAMRole) ProviderID() string {
+	return r.RoleAWSID
+}
+
+// ComponentName returns a components name
+func (r IAMRole) ComponentName() string {
+	return r.Name
+}
+
+// copyProviderData copies any values generated by aws from a previous iam role
+func (r *IAMRole) copyProviderData(or *IAMRole) {
+	r.RoleAWSID = or.RoleAWSID
+	r.InstanceProfileAWSID = or.InstanceProfileAWSID
+	r.ProviderType = "$(datacenters.items.0.type)"
+	r.DatacenterName = "$(datacenters.items.0.name)"
+	r.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	r.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	r.DatacenterRegion = "$(datacenters.items.0.region)"
+}
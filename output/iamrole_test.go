@@ -0,0 +1,58 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testIAMRole() IAMRole {
+	return IAMRole{
+		Name:             "test",
+		AssumeRolePolicy: `{"Statement":[]}`,
+		Policies: []IAMPolicy{
+			IAMPolicy{Name: "s3", Document: `{"Statement":[]}`},
+		},
+		ManagedPolicies: []string{"arn:aws:iam::aws:policy/ReadOnlyAccess"},
+	}
+}
+
+func TestIAMRoleHasChanged(t *testing.T) {
+	Convey("Given an iam role", t, func() {
+		r := testIAMRole()
+
+		Convey("When I compare it to an identical iam role", func() {
+			or := testIAMRole()
+			change := r.HasChanged(&or)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When I compare it to an iam role with a different policy document", func() {
+			or := testIAMRole()
+			or.Policies[0].Document = `{}`
+			change := r.HasChanged(&or)
+			Convey("Then it should return the changes", func() {
+				So(change, ShouldResemble, []Change{{Field: "policies.s3.document", Old: `{}`, New: `{"Statement":[]}`}})
+			})
+		})
+
+		Convey("When I compare it to an iam role with different policies", func() {
+			or := testIAMRole()
+			or.Policies[0].Name = "ec2"
+			or.ManagedPolicies = nil
+			change := r.HasChanged(&or)
+			Convey("Then it should return the changes", func() {
+				So(len(change), ShouldEqual, 3)
+				So(change[0], ShouldResemble, Change{Field: "policies", Old: "ec2"})
+				So(change[1], ShouldResemble, Change{Field: "policies", New: "s3"})
+				So(change[2].Field, ShouldEqual, "managed_policies")
+			})
+		})
+	})
+}
@@ -4,10 +4,7 @@
 
 package output
 
-import (
-	"net"
-	"reflect"
-)
+import "net"
 
 // InstanceVolume ...
 type InstanceVolume struct {
@@ -30,6 +27,8 @@ type Instance struct {
 	AssignElasticIP     bool              `json:"assign_elastic_ip"`
 	KeyPair             string            `json:"key_pair"`
 	UserData            string            `json:"user_data"`
+	IAMProfile          string            `json:"iam_profile"`
+	IAMProfileAWSID     string            `json:"iam_profile_aws_id"`
 	Network             string            `json:"network_name"`
 	NetworkAWSID        string            `json:"network_aws_id"`
 	NetworkIsPublic     bool              `json:"network_is_public"`
@@ -44,29 +43,48 @@ type Instance struct {
 	SecretAccessKey     string            `json:"aws_secret_access_key"`
 	VpcID               string            `json:"vpc_id"`
 	Service             string            `json:"service"`
+	Changes             []Change          `json:"changes,omitempty"`
 	Status              string            `json:"status"`
 	Exists              bool
 }
 
-// HasChanged diff's the two items and returns true if there have been any changes
-func (i *Instance) HasChanged(oi *Instance) bool {
-	if i.Type != oi.Type {
-		return true
+// HasChanged diff's the two items and returns any changes between them.
+// The image, key pair, user data, network and ip are only applied when an
+// instance is launched, so changing any of them requires the instance to be
+// replaced.
+// An elastic ip can be associated or released on a running instance
+func (i *Instance) HasChanged(oi *Instance) []Change {
+	var c []Change
+
+	c = diffReplace(c, "image", oi.Image, i.Image)
+	c = diffReplace(c, "key_pair", oi.KeyPair, i.KeyPair)
+	c = diffSensitiveReplace(c, "user_data", oi.UserData, i.UserData)
+	c = diffReplace(c, "network_name", oi.Network, i.Network)
+	c = diffReplace(c, "ip", ipString(oi.IP), ipString(i.IP))
+	c = diffBool(c, "elastic_ip", oi.AssignElasticIP, i.AssignElasticIP)
+	c = diffString(c, "instance_type", oi.Type, i.Type)
+	c = diffString(c, "iam_profile", oi.IAMProfile, i.IAMProfile)
+
+	for _, v := range oi.Volumes {
+		if hasVolume(i.Volumes, v.Volume) != true {
+			c = append(c, Change{Field: "volumes", Old: v.Volume})
+		}
 	}
 
 	for _, v := range i.Volumes {
 		if hasVolume(oi.Volumes, v.Volume) != true {
-			return true
+			c = append(c, Change{Field: "volumes", New: v.Volume})
 		}
 	}
 
-	for _, v := range oi.Volumes {
-		if hasVolume(i.Volumes, v.Volume) != true {
-			return true
-		}
-	}
+	return diffStrings(c, "security_groups", oi.SecurityGroups, i.SecurityGroups)
+}
 
-	return !reflect.DeepEqual(i.SecurityGroups, oi.SecurityGroups)
+func ipString(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
 }
 
 func hasVolume(vols []InstanceVolume, volume string) bool {
@@ -93,3 +111,17 @@ func (i Instance) ProviderID() string {
 func (i Instance) ComponentName() string {
 	return i.Name
 }
+
+// copyProviderData copies any values generated by aws from a previous instance
+func (i *Instance) copyProviderData(oi *Instance) {
+	i.InstanceAWSID = oi.InstanceAWSID
+	i.PublicIP = oi.PublicIP
+	i.ElasticIP = oi.ElasticIP
+	i.ElasticIPAWSID = oi.ElasticIPAWSID
+	i.DatacenterType = "$(datacenters.items.0.type)"
+	i.DatacenterName = "$(datacenters.items.0.name)"
+	i.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	i.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	i.DatacenterRegion = "$(datacenters.items.0.region)"
+	i.VpcID = "$(vpcs.items.0.vpc_id)"
+}
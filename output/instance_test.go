@@ -5,6 +5,7 @@
 package output
 
 import (
+	"net"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -27,8 +28,67 @@ func TestInstanceHasChanged(t *testing.T) {
 				Network: "network",
 			}
 			change := i.HasChanged(&oi)
-			Convey("Then it should return true", func() {
-				So(change, ShouldBeTrue)
+			Convey("Then it should return the changes", func() {
+				So(change, ShouldNotBeEmpty)
+				So(len(change), ShouldEqual, 1)
+				So(change[0].Field, ShouldEqual, "instance_type")
+				So(change[0].String(), ShouldEqual, "instance_type m2.large → m2.small")
+			})
+		})
+
+		Convey("When I compare it to an instance with a different key pair, user data and ip", func() {
+			i.KeyPair = "new"
+			i.UserData = "#!/bin/sh"
+			i.IP = net.ParseIP("10.0.0.11")
+			oi := Instance{
+				Name:     "test",
+				Type:     "m2.small",
+				Image:    "ami-000000",
+				Network:  "network",
+				KeyPair:  "old",
+				UserData: "#!/bin/bash",
+				IP:       net.ParseIP("10.0.0.10"),
+			}
+			change := i.HasChanged(&oi)
+			Convey("Then it should require a replacement", func() {
+				So(len(change), ShouldEqual, 3)
+				So(change[0], ShouldResemble, Change{Field: "key_pair", Old: "old", New: "new", Replace: true})
+				So(change[1], ShouldResemble, Change{Field: "user_data", Old: SENSITIVE, New: SENSITIVE, Replace: true})
+				So(change[2], ShouldResemble, Change{Field: "ip", Old: "10.0.0.10", New: "10.0.0.11", Replace: true})
+			})
+
+			Convey("And the stored user data is redacted", func() {
+				oi.UserData = "(redacted)"
+				Convey("Then the user data should not be reported", func() {
+					So(len(i.HasChanged(&oi)), ShouldEqual, 2)
+				})
+			})
+		})
+
+		Convey("When I compare it to an instance without an elastic ip", func() {
+			i.AssignElasticIP = true
+			oi := Instance{
+				Name:    "test",
+				Type:    "m2.small",
+				Image:   "ami-000000",
+				Network: "network",
+			}
+			change := i.HasChanged(&oi)
+			Convey("Then it should update the instance in place", func() {
+				So(change, ShouldResemble, []Change{{Field: "elastic_ip", Old: "false", New: "true"}})
+				So(RequiresReplacement(change), ShouldBeFalse)
+			})
+		})
+
+		Convey("When I compare it to an instance on a different network", func() {
+			oi := Instance{
+				Name:    "test",
+				Type:    "m2.small",
+				Image:   "ami-000000",
+				Network: "other",
+			}
+			Convey("Then it should require a replacement", func() {
+				So(RequiresReplacement(i.HasChanged(&oi)), ShouldBeTrue)
 			})
 		})
 
@@ -40,8 +100,8 @@ func TestInstanceHasChanged(t *testing.T) {
 				Network: "network",
 			}
 			change := i.HasChanged(&oi)
-			Convey("Then it should return false", func() {
-				So(change, ShouldBeFalse)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
 			})
 		})
 	})
@@ -4,8 +4,6 @@
 
 package output
 
-import "reflect"
-
 // Nat : mapping of a nat component
 type Nat struct {
 	Name                   string            `json:"name"`
@@ -25,17 +23,14 @@ type Nat struct {
 	SecretAccessKey        string            `json:"aws_secret_access_key"`
 	VpcID                  string            `json:"vpc_id"`
 	Service                string            `json:"service"`
+	Changes                []Change          `json:"changes,omitempty"`
 	Status                 string            `json:"status"`
 	Exists                 bool
 }
 
-// HasChanged diff's the two items and returns true if there have been any changes
-func (n *Nat) HasChanged(on *Nat) bool {
-	if !reflect.DeepEqual(n.RoutedNetworks, on.RoutedNetworks) {
-		return true
-	}
-
-	return false
+// HasChanged diff's the two items and returns any changes between them
+func (n *Nat) HasChanged(on *Nat) []Change {
+	return diffStrings(nil, "routed_networks", on.RoutedNetworks, n.RoutedNetworks)
 }
 
 func hasNetwork(networks []string, name string) bool {
@@ -62,3 +57,17 @@ func (n Nat) ProviderID() string {
 func (n Nat) ComponentName() string {
 	return n.Name
 }
+
+// copyProviderData copies any values generated by aws from a previous nat gateway
+func (n *Nat) copyProviderData(on *Nat) {
+	n.NatGatewayAWSID = on.NatGatewayAWSID
+	n.NatGatewayAllocationID = on.NatGatewayAllocationID
+	n.NatGatewayAllocationIP = on.NatGatewayAllocationIP
+	n.ProviderType = "$(datacenters.items.0.type)"
+	n.DatacenterType = "$(datacenters.items.0.type)"
+	n.DatacenterName = "$(datacenters.items.0.name)"
+	n.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	n.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	n.DatacenterRegion = "$(datacenters.items.0.region)"
+	n.VpcID = "$(vpcs.items.0.vpc_id)"
+}
@@ -20,13 +20,28 @@ type Network struct {
 	SecretAccessKey  string            `json:"aws_secret_access_key"`
 	VpcID            string            `json:"vpc_id"`
 	Service          string            `json:"service"`
+	Changes          []Change          `json:"changes,omitempty"`
 	Status           string            `json:"status"`
 	Exists           bool
 }
 
-// HasChanged diff's the two items and returns true if there have been any changes
-func (n *Network) HasChanged(on *Network) bool {
-	return false
+// HasChanged diff's the two items and returns any changes between them.
+// A subnet can't be moved to another range or availability zone, so those
+// changes require the network to be replaced
+func (n *Network) HasChanged(on *Network) []Change {
+	var c []Change
+
+	c = diffReplace(c, "range", on.Subnet, n.Subnet)
+
+	// networks without an availability zone are placed by aws
+	if n.AvailabilityZone != "" {
+		c = diffReplace(c, "availability_zone", on.AvailabilityZone, n.AvailabilityZone)
+	}
+
+	c = diffBool(c, "is_public", on.IsPublic, n.IsPublic)
+	c = diffString(c, "nat_gateway", on.Tags["ernest.nat_gateway"], n.Tags["ernest.nat_gateway"])
+
+	return diffTags(c, on.Tags, n.Tags, "ernest.nat_gateway")
 }
 
 // GetTags returns a components tags
@@ -43,3 +58,17 @@ func (n Network) ProviderID() string {
 func (n Network) ComponentName() string {
 	return n.Name
 }
+
+// copyProviderData copies any values generated by aws from a previous network
+func (n *Network) copyProviderData(on *Network) {
+	n.NetworkAWSID = on.NetworkAWSID
+	if n.AvailabilityZone == "" {
+		n.AvailabilityZone = on.AvailabilityZone
+	}
+	n.DatacenterType = "$(datacenters.items.0.type)"
+	n.DatacenterName = "$(datacenters.items.0.name)"
+	n.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	n.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	n.DatacenterRegion = "$(datacenters.items.0.region)"
+	n.VpcID = "$(vpcs.items.0.vpc_id)"
+}
@@ -13,30 +13,103 @@ import (
 func TestNetworkHasChanged(t *testing.T) {
 	Convey("Given a network", t, func() {
 		n := Network{
-			Name:   "test",
-			Subnet: "10.0.0.0/24",
+			Name:             "test",
+			Subnet:           "10.0.0.0/24",
+			AvailabilityZone: "eu-west-1a",
+			Tags:             map[string]string{"Name": "test", "ernest.nat_gateway": "gw"},
 		}
 
-		// Networks are immutable
-		Convey("When I compare it to an changed network", func() {
+		Convey("When I compare it to a network with a different range", func() {
 			on := Network{
-				Name:   "test",
-				Subnet: "10.10.0.0/24",
+				Name:             "test",
+				Subnet:           "10.10.0.0/24",
+				AvailabilityZone: "eu-west-1a",
+				Tags:             map[string]string{"Name": "test", "ernest.nat_gateway": "gw"},
 			}
 			change := n.HasChanged(&on)
-			Convey("Then it should return false", func() {
-				So(change, ShouldBeFalse)
+			Convey("Then it should require a replacement", func() {
+				So(change, ShouldResemble, []Change{{Field: "range", Old: "10.10.0.0/24", New: "10.0.0.0/24", Replace: true}})
+				So(RequiresReplacement(change), ShouldBeTrue)
 			})
 		})
 
-		Convey("When I compare it to an identical network", func() {
+		Convey("When I compare it to a network in a different availability zone", func() {
 			on := Network{
-				Name:   "test",
-				Subnet: "10.0.0.0/24",
+				Name:             "test",
+				Subnet:           "10.0.0.0/24",
+				AvailabilityZone: "eu-west-1b",
+				Tags:             map[string]string{"Name": "test", "ernest.nat_gateway": "gw"},
 			}
 			change := n.HasChanged(&on)
-			Convey("Then it should return false", func() {
-				So(change, ShouldBeFalse)
+			Convey("Then it should require a replacement", func() {
+				So(RequiresReplacement(change), ShouldBeTrue)
+				So(change[0].Field, ShouldEqual, "availability_zone")
+			})
+
+			Convey("And the network does not specify an availability zone", func() {
+				n.AvailabilityZone = ""
+				Convey("Then it should return no changes", func() {
+					So(n.HasChanged(&on), ShouldBeEmpty)
+				})
+			})
+		})
+
+		Convey("When I compare it to a network with a different nat gateway and public flag", func() {
+			on := Network{
+				Name:             "test",
+				Subnet:           "10.0.0.0/24",
+				AvailabilityZone: "eu-west-1a",
+				IsPublic:         true,
+				Tags:             map[string]string{"Name": "test", "Owner": "ops"},
+			}
+			change := n.HasChanged(&on)
+			Convey("Then it should return in place changes", func() {
+				So(len(change), ShouldEqual, 3)
+				So(change[0], ShouldResemble, Change{Field: "is_public", Old: "true", New: "false"})
+				So(change[1], ShouldResemble, Change{Field: "nat_gateway", New: "gw"})
+				So(change[2], ShouldResemble, Change{Field: "tags.Owner", Old: "ops"})
+				So(RequiresReplacement(change), ShouldBeFalse)
+			})
+		})
+
+		Convey("When I compare it to an identical network", func() {
+			on := n
+			change := n.HasChanged(&on)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestDiffNetworks(t *testing.T) {
+	Convey("Given a previous mapping with networks", t, func() {
+		var m, om FSMMessage
+		om.Networks.Items = []Network{
+			{Name: "web", Subnet: "10.0.0.0/24", NetworkAWSID: "subnet-1", Status: "completed"},
+			{Name: "db", Subnet: "10.0.1.0/24", NetworkAWSID: "subnet-2", Status: "completed"},
+		}
+
+		Convey("When one network changes its range and another its nat gateway", func() {
+			m.Networks.Items = []Network{
+				{Name: "web", Subnet: "10.0.2.0/24"},
+				{Name: "db", Subnet: "10.0.1.0/24", Tags: map[string]string{"ernest.nat_gateway": "gw"}},
+			}
+			m.Diff(om)
+
+			Convey("Then the changed range should replace the network", func() {
+				So(len(m.NetworksToDelete.Items), ShouldEqual, 1)
+				So(m.NetworksToDelete.Items[0].NetworkAWSID, ShouldEqual, "subnet-1")
+				So(m.NetworksToDelete.Items[0].Status, ShouldEqual, "")
+				So(len(m.NetworksToCreate.Items), ShouldEqual, 1)
+				So(m.NetworksToCreate.Items[0].Subnet, ShouldEqual, "10.0.2.0/24")
+				So(m.NetworksToCreate.Items[0].Changes[0].Replace, ShouldBeTrue)
+			})
+
+			Convey("Then the nat gateway change should update the network", func() {
+				So(len(m.NetworksToUpdate.Items), ShouldEqual, 1)
+				So(m.NetworksToUpdate.Items[0].Name, ShouldEqual, "db")
+				So(m.NetworksToUpdate.Items[0].Changes, ShouldResemble, []Change{{Field: "nat_gateway", New: "gw"}})
 			})
 		})
 	})
@@ -0,0 +1,72 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+const (
+	// ACTIONCREATE : Component will be created
+	ACTIONCREATE = "create"
+	// ACTIONUPDATE : Component will be updated
+	ACTIONUPDATE = "update"
+	// ACTIONDELETE : Component will be deleted
+	ACTIONDELETE = "delete"
+	// ACTIONREPLACE : Component will be deleted and created again
+	ACTIONREPLACE = "replace"
+)
+
+// PlanItem : A single change that a build would apply to a component
+type PlanItem struct {
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	Action  string   `json:"action"`
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// Plan : Human readable change set of a build
+type Plan struct {
+	ID      string     `json:"id"`
+	Service string     `json:"service"`
+	Name    string     `json:"name"`
+	Items   []PlanItem `json:"items"`
+}
+
+// Plan builds a change set from a diffed FSMMessage
+func (m *FSMMessage) Plan() Plan {
+	p := Plan{
+		ID:      m.ID,
+		Service: m.Service,
+		Name:    m.ServiceName,
+		Items:   []PlanItem{},
+	}
+
+	for _, t := range ComponentTypes {
+		for _, c := range m.Components(t.List(ACTIONCREATE)) {
+			if m.IsReplaced(t.Collection, t.Name(c)) {
+				p.add(t.Type, t.Name(c), ACTIONREPLACE, t.changes(c))
+				continue
+			}
+			p.add(t.Type, t.Name(c), ACTIONCREATE, nil)
+		}
+		for _, c := range m.Components(t.List(ACTIONUPDATE)) {
+			p.add(t.Type, t.Name(c), ACTIONUPDATE, t.changes(c))
+		}
+		for _, c := range m.Components(t.List(ACTIONDELETE)) {
+			if m.IsReplaced(t.Collection, t.Name(c)) {
+				continue
+			}
+			p.add(t.Type, t.Name(c), ACTIONDELETE, nil)
+		}
+	}
+
+	return p
+}
+
+func (p *Plan) add(ctype, name, action string, changes []Change) {
+	p.Items = append(p.Items, PlanItem{
+		Type:    ctype,
+		Name:    name,
+		Action:  action,
+		Changes: changes,
+	})
+}
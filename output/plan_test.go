@@ -0,0 +1,102 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPlan(t *testing.T) {
+	Convey("Given a previous build", t, func() {
+		var om FSMMessage
+		om.Instances.Items = []Instance{
+			{Name: "web-1", Type: "t2.micro", Image: "ami-000000"},
+			{Name: "web-2", Type: "t2.micro", Image: "ami-000000"},
+		}
+		om.S3s.Items = []S3{
+			{Name: "bucket", ACL: "private"},
+		}
+
+		Convey("When I plan a build that changes components", func() {
+			var m FSMMessage
+			m.Instances.Items = []Instance{
+				{Name: "web-1", Type: "t2.large", Image: "ami-000000"},
+				{Name: "web-3", Type: "t2.micro", Image: "ami-000000"},
+			}
+			m.S3s.Items = []S3{
+				{Name: "bucket", ACL: "private"},
+			}
+
+			m.Diff(om)
+			p := m.Plan()
+
+			Convey("Then it should list every change", func() {
+				So(len(p.Items), ShouldEqual, 3)
+				So(p.Items[0].Type, ShouldEqual, "instance")
+				So(p.Items[0].Name, ShouldEqual, "web-3")
+				So(p.Items[0].Action, ShouldEqual, ACTIONCREATE)
+				So(p.Items[1].Name, ShouldEqual, "web-1")
+				So(p.Items[1].Action, ShouldEqual, ACTIONUPDATE)
+				So(p.Items[1].Changes, ShouldResemble, []Change{{Field: "instance_type", Old: "t2.micro", New: "t2.large"}})
+				So(p.Items[2].Name, ShouldEqual, "web-2")
+				So(p.Items[2].Action, ShouldEqual, ACTIONDELETE)
+			})
+		})
+
+		Convey("When I plan a build that changes an instance image and bucket location", func() {
+			var m FSMMessage
+			m.Instances.Items = []Instance{
+				{Name: "web-1", Type: "t2.micro", Image: "ami-111111"},
+				{Name: "web-2", Type: "t2.micro", Image: "ami-000000"},
+			}
+			m.S3s.Items = []S3{
+				{Name: "bucket", ACL: "private", BucketLocation: "eu-west-2"},
+			}
+
+			m.Diff(om)
+			p := m.Plan()
+
+			Convey("Then it should delete and create the components again", func() {
+				So(len(m.InstancesToDelete.Items), ShouldEqual, 1)
+				So(len(m.InstancesToCreate.Items), ShouldEqual, 1)
+				So(len(m.InstancesToUpdate.Items), ShouldEqual, 0)
+				So(len(m.S3sToDelete.Items), ShouldEqual, 1)
+				So(len(m.S3sToCreate.Items), ShouldEqual, 1)
+				So(m.Replacements, ShouldResemble, map[string][]string{"instances": {"web-1"}, "s3s": {"bucket"}})
+			})
+
+			Convey("Then it should flag the replacements", func() {
+				So(len(p.Items), ShouldEqual, 2)
+				So(p.Items[0].Name, ShouldEqual, "web-1")
+				So(p.Items[0].Action, ShouldEqual, ACTIONREPLACE)
+				So(p.Items[0].Changes, ShouldResemble, []Change{{Field: "image", Old: "ami-000000", New: "ami-111111", Replace: true}})
+				So(p.Items[1].Type, ShouldEqual, "s3")
+				So(p.Items[1].Action, ShouldEqual, ACTIONREPLACE)
+			})
+
+			Convey("Then the workflow should delete them before creating them", func() {
+				arcs, err := m.BuildArcs("create")
+				So(err, ShouldBeNil)
+				So(hasArc(arcs, "instances_deleted", "creating_instances"), ShouldBeTrue)
+				So(hasArc(arcs, "s3s_deleted", "creating_s3s"), ShouldBeTrue)
+			})
+		})
+
+		Convey("When I plan a build without changes", func() {
+			var m FSMMessage
+			m.Instances.Items = om.Instances.Items
+			m.S3s.Items = om.S3s.Items
+
+			m.Diff(om)
+			p := m.Plan()
+
+			Convey("Then it should be empty", func() {
+				So(len(p.Items), ShouldEqual, 0)
+			})
+		})
+	})
+}
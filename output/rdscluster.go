@@ -4,8 +4,6 @@
 
 package output
 
-import "reflect"
-
 // RDSCluster ...
 type RDSCluster struct {
 	ProviderType        string            `json:"_type"`
@@ -33,41 +31,28 @@ type RDSCluster struct {
 	MaintenanceWindow   string            `json:"maintenance_window,omitempty"`
 	ReplicationSource   string            `json:"replication_source,omitempty"`
 	FinalSnapshot       bool              `json:"final_snapshot"`
+	Changes             []Change          `json:"changes,omitempty"`
 	Status              string            `json:"status"`
 	Exists              bool
 }
 
-// HasChanged diff's the two items and returns true if there have been any changes
-func (r *RDSCluster) HasChanged(or *RDSCluster) bool {
-	if r.Port != nil && or.Port != nil {
-		if *r.Port != *or.Port {
-			return true
-		}
-	}
-
-	if r.DatabasePassword != or.DatabasePassword {
-		return true
-	}
-
-	if r.BackupRetention != nil && or.BackupRetention != nil {
-		if *r.BackupRetention != *or.BackupRetention {
-			return true
-		}
-	}
-
-	if r.BackupWindow != or.BackupWindow {
-		return true
-	}
+// HasChanged diff's the two items and returns any changes between them.
+// The engine and database of an existing cluster can't be changed, so they
+// require the cluster to be replaced
+func (r *RDSCluster) HasChanged(or *RDSCluster) []Change {
+	var c []Change
 
-	if r.MaintenanceWindow != or.MaintenanceWindow {
-		return true
-	}
+	c = diffReplace(c, "engine", or.Engine, r.Engine)
+	c = diffReplace(c, "database_name", or.DatabaseName, r.DatabaseName)
+	c = diffReplace(c, "database_username", or.DatabaseUsername, r.DatabaseUsername)
+	c = diffInt64(c, "port", or.Port, r.Port)
+	c = diffSensitive(c, "database_password", or.DatabasePassword, r.DatabasePassword)
+	c = diffInt64(c, "backup_retention", or.BackupRetention, r.BackupRetention)
+	c = diffString(c, "backup_window", or.BackupWindow, r.BackupWindow)
+	c = diffString(c, "maintenance_window", or.MaintenanceWindow, r.MaintenanceWindow)
+	c = diffStrings(c, "networks", or.Networks, r.Networks)
 
-	if reflect.DeepEqual(r.Networks, or.Networks) != true {
-		return true
-	}
-
-	return !reflect.DeepEqual(r.SecurityGroups, or.SecurityGroups)
+	return diffStrings(c, "security_groups", or.SecurityGroups, r.SecurityGroups)
 }
 
 // GetTags returns a components tags
@@ -84,3 +69,12 @@ func (r RDSCluster) ProviderID() string {
 func (r RDSCluster) ComponentName() string {
 	return r.Name
 }
+
+// copyProviderData copies any values generated by aws from a previous rds cluster
+func (r *RDSCluster) copyProviderData(or *RDSCluster) {
+	r.ARN = or.ARN
+	r.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	r.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	r.DatacenterRegion = "$(datacenters.items.0.region)"
+	r.Endpoint = or.Endpoint
+}
@@ -57,8 +57,17 @@ func TestRDSClusterHasChanged(t *testing.T) {
 				},
 			}
 			change := r.HasChanged(&or)
-			Convey("Then it should return true", func() {
-				So(change, ShouldBeTrue)
+			Convey("Then it should return the changes", func() {
+				So(change, ShouldNotBeEmpty)
+			})
+		})
+
+		Convey("When I compare it to a rds cluster with a different engine", func() {
+			or := r
+			or.Engine = "aurora-postgresql"
+			change := r.HasChanged(&or)
+			Convey("Then it should require a replacement", func() {
+				So(change, ShouldResemble, []Change{{Field: "engine", Old: "aurora-postgresql", New: "aurora", Replace: true}})
 			})
 		})
 
@@ -83,8 +92,8 @@ func TestRDSClusterHasChanged(t *testing.T) {
 				},
 			}
 			change := r.HasChanged(&or)
-			Convey("Then it should return false", func() {
-				So(change, ShouldBeFalse)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
 			})
 		})
 	})
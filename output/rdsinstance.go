@@ -4,8 +4,6 @@
 
 package output
 
-import "reflect"
-
 // RDSInstance ...
 type RDSInstance struct {
 	ProviderType        string            `json:"_type"`
@@ -44,79 +42,37 @@ type RDSInstance struct {
 	ReplicationSource   string            `json:"replication_source,omitempty"`
 	License             string            `json:"license,omitempty"`
 	Timezone            string            `json:"timezone,omitempty"`
+	Changes             []Change          `json:"changes,omitempty"`
 	Status              string            `json:"status"`
 	Exists              bool
 }
 
-// HasChanged diff's the two items and returns true if there have been any changes
-func (r *RDSInstance) HasChanged(or *RDSInstance) bool {
-	if r.Size != or.Size {
-		return true
-	}
-
-	if r.EngineVersion != or.EngineVersion {
-		return true
-	}
-
-	if r.Port != nil && or.Port != nil {
-		if *r.Port != *or.Port {
-			return true
-		}
-	}
-
-	if r.StorageSize != nil && or.StorageSize != nil {
-		if *r.StorageSize != *or.StorageSize {
-			return true
-		}
-	}
-
-	if r.StorageIops != nil && or.StorageIops != nil {
-		if *r.StorageIops != *or.StorageIops {
-			return true
-		}
-	}
-
-	if r.StorageType != or.StorageType {
-		return true
-	}
-
-	if r.MultiAZ != or.MultiAZ {
-		return true
-	}
-
-	if r.PromotionTier != nil && or.PromotionTier != nil {
-		if *r.PromotionTier != *or.PromotionTier {
-			return true
-		}
-	}
-
-	if r.AutoUpgrade != or.AutoUpgrade {
-		return true
-	}
-
-	if r.BackupRetention != nil && or.BackupRetention != nil {
-		if *r.BackupRetention != *or.BackupRetention {
-			return true
-		}
-	}
-
-	if r.BackupWindow != or.BackupWindow {
-		return true
-	}
-
-	if r.DatabasePassword != or.DatabasePassword {
-		return true
-	}
-
-	if r.Public != or.Public {
-		return true
-	}
-
-	if reflect.DeepEqual(r.SecurityGroups, or.SecurityGroups) != true {
-		return true
-	}
-
-	return !reflect.DeepEqual(r.Networks, or.Networks)
+// HasChanged diff's the two items and returns any changes between them.
+// The engine, cluster and database of an existing instance can't be
+// changed, so they require the instance to be replaced
+func (r *RDSInstance) HasChanged(or *RDSInstance) []Change {
+	var c []Change
+
+	c = diffReplace(c, "engine", or.Engine, r.Engine)
+	c = diffReplace(c, "cluster", or.Cluster, r.Cluster)
+	c = diffReplace(c, "database_name", or.DatabaseName, r.DatabaseName)
+	c = diffReplace(c, "database_username", or.DatabaseUsername, r.DatabaseUsername)
+	c = diffString(c, "size", or.Size, r.Size)
+	c = diffString(c, "engine_version", or.EngineVersion, r.EngineVersion)
+	c = diffInt64(c, "port", or.Port, r.Port)
+	c = diffInt64(c, "storage_size", or.StorageSize, r.StorageSize)
+	c = diffInt64(c, "storage_iops", or.StorageIops, r.StorageIops)
+	c = diffString(c, "storage_type", or.StorageType, r.StorageType)
+	c = diffBool(c, "multi_az", or.MultiAZ, r.MultiAZ)
+	c = diffInt64(c, "promotion_tier", or.PromotionTier, r.PromotionTier)
+	c = diffBool(c, "auto_upgrade", or.AutoUpgrade, r.AutoUpgrade)
+	c = diffInt64(c, "backup_retention", or.BackupRetention, r.BackupRetention)
+	c = diffString(c, "backup_window", or.BackupWindow, r.BackupWindow)
+	c = diffSensitive(c, "database_password", or.DatabasePassword, r.DatabasePassword)
+	c = diffBool(c, "public", or.Public, r.Public)
+	c = diffStrings(c, "security_groups", or.SecurityGroups, r.SecurityGroups)
+
+	return diffStrings(c, "networks", or.Networks, r.Networks)
 }
 
 // GetTags returns a components tags
@@ -133,3 +89,12 @@ func (r RDSInstance) ProviderID() string {
 func (r RDSInstance) ComponentName() string {
 	return r.Name
 }
+
+// copyProviderData copies any values generated by aws from a previous rds instance
+func (r *RDSInstance) copyProviderData(or *RDSInstance) {
+	r.ARN = or.ARN
+	r.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	r.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	r.DatacenterRegion = "$(datacenters.items.0.region)"
+	r.Endpoint = or.Endpoint
+}
@@ -77,8 +77,8 @@ func TestRDSInstanceHasChanged(t *testing.T) {
 				},
 			}
 			change := r.HasChanged(&or)
-			Convey("Then it should return true", func() {
-				So(change, ShouldBeTrue)
+			Convey("Then it should return the changes", func() {
+				So(change, ShouldNotBeEmpty)
 			})
 		})
 
@@ -115,8 +115,8 @@ func TestRDSInstanceHasChanged(t *testing.T) {
 				},
 			}
 			change := r.HasChanged(&or)
-			Convey("Then it should return false", func() {
-				So(change, ShouldBeFalse)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
 			})
 		})
 	})
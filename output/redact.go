@@ -0,0 +1,31 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"encoding/json"
+
+	"github.com/ernestio/aws-definition-mapper/secret"
+)
+
+// Redacted returns a copy of the message with every credential and secret
+// redacted. It should be used for anything that is logged or stored
+func (m *FSMMessage) Redacted() (*FSMMessage, error) {
+	var r FSMMessage
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+
+	r.RedactSecrets()
+	secret.Redact(&r)
+
+	return &r, nil
+}
@@ -0,0 +1,71 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ernestio/aws-definition-mapper/secret"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRedacted(t *testing.T) {
+	Convey("Given a message carrying credentials and secrets", t, func() {
+		var m FSMMessage
+		m.Datacenters.Items = append(m.Datacenters.Items, Datacenter{Name: "dc", AccessKeyID: "AKIAEXAMPLEKEY", SecretAccessKey: "wJalrXUtnFEMIEXAMPLE", Password: "dcpassword"})
+		m.Instances.Items = append(m.Instances.Items, Instance{Name: "web-1", UserData: "export TOKEN=usrdatatoken", AccessKeyID: "AKIAEXAMPLEKEY", SecretAccessKey: "wJalrXUtnFEMIEXAMPLE"})
+		m.AutoscalingGroups.Items = append(m.AutoscalingGroups.Items, AutoscalingGroup{Name: "asg", LaunchConfiguration: LaunchConfiguration{UserData: "export TOKEN=usrdatatoken"}})
+		m.RDSClusters.Items = append(m.RDSClusters.Items, RDSCluster{Name: "cluster", DatabasePassword: "plainpassword"})
+		m.RDSInstances.Items = append(m.RDSInstances.Items, RDSInstance{Name: "db", DatabasePassword: "secret://env/REDACT_TEST_PASSWORD", AccessKeyID: "$(datacenters.items.0.aws_access_key_id)"})
+		m.Diff(FSMMessage{})
+
+		// mappings stored by previous versions hold resolved secrets
+		m.RDSInstancesToCreate.Items[0].DatabasePassword = "r3f3r3ncedpassword"
+		m.SecretRefs = map[string]string{"rds_instances.db.database_password": "secret://env/REDACT_TEST_PASSWORD"}
+
+		Convey("When redacting it", func() {
+			r, err := m.Redacted()
+			So(err, ShouldBeNil)
+
+			data, err := json.Marshal(r)
+			So(err, ShouldBeNil)
+
+			Convey("Then no secret should be present in clear text", func() {
+				for _, s := range []string{"AKIAEXAMPLEKEY", "wJalrXUtnFEMIEXAMPLE", "dcpassword", "usrdatatoken", "plainpassword", "r3f3r3ncedpassword"} {
+					So(string(data), ShouldNotContainSubstring, s)
+				}
+			})
+
+			Convey("Then resolved secrets should be replaced with their reference", func() {
+				So(r.RDSInstancesToCreate.Items[0].DatabasePassword, ShouldEqual, "secret://env/REDACT_TEST_PASSWORD")
+				So(r.RDSClustersToCreate.Items[0].DatabasePassword, ShouldEqual, secret.REDACTED)
+				So(r.Datacenters.Items[0].SecretAccessKey, ShouldEqual, secret.REDACTED)
+			})
+
+			Convey("Then mapping templates should be kept", func() {
+				So(r.RDSInstancesToCreate.Items[0].AccessKeyID, ShouldEqual, "$(datacenters.items.0.aws_access_key_id)")
+			})
+
+			Convey("Then the original message should not be changed", func() {
+				So(m.Datacenters.Items[0].SecretAccessKey, ShouldEqual, "wJalrXUtnFEMIEXAMPLE")
+				So(m.RDSInstancesToCreate.Items[0].DatabasePassword, ShouldEqual, "r3f3r3ncedpassword")
+				So(m.InstancesToCreate.Items[0].UserData, ShouldEqual, "export TOKEN=usrdatatoken")
+			})
+		})
+	})
+
+	Convey("Given a stored instance with redacted user data", t, func() {
+		oi := Instance{Name: "web-1", UserData: secret.REDACTED}
+		i := Instance{Name: "web-1", UserData: "#!/bin/sh"}
+
+		Convey("When checking it for changes", func() {
+			c := i.HasChanged(&oi)
+			Convey("Then the unknown user data should not be reported as a change", func() {
+				So(len(c), ShouldEqual, 0)
+			})
+		})
+	})
+}
@@ -0,0 +1,496 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ComponentType : Describes how a type of component is stored on a
+// FSMMessage and how it is compared against a previous build
+type ComponentType struct {
+	// Type : name of the component type used on plans
+	Type string
+	// Collection : json name of the collection holding the components.
+	// Any create, update or delete lists are stored on the message as
+	// <collection>_to_<action>
+	Collection string
+	// Name : returns the name a component is identified by on its collection
+	Name func(c Component) string
+	// Status : returns the status of a component
+	Status func(c Component) *string
+	// Changes : returns the changes recorded on a component. Components
+	// that are never updated or replaced don't need to set it
+	Changes func(c Component) *[]Change
+	// HasChanged : returns the changes between a component and its
+	// previous version. Components are never updated when not set
+	HasChanged func(c, o Component) []Change
+	// ProviderData : copies any data generated by the provider from a
+	// previous version of a component
+	ProviderData func(c, o Component)
+	// Diff : replaces the default diff of the collection
+	Diff func(m *FSMMessage, om FSMMessage)
+	// References : returns true if a component uses the values generated
+	// for a named component of another collection. Components are updated
+	// when a component they reference is replaced
+	References func(c Component, collection, name string) bool
+	// Retain : components are kept when the service is deleted
+	Retain bool
+}
+
+// ComponentTypes : All registered component types, in the order they are
+// diffed and planned
+var ComponentTypes []ComponentType
+
+// collectionFields holds the index of every collection on a FSMMessage,
+// keyed by its json name
+var collectionFields = collectionIndex()
+
+func init() {
+	RegisterComponentType(ComponentType{
+		Type:       "vpc",
+		Collection: "vpcs",
+		Name:       func(c Component) string { return c.(*VPC).ComponentName() },
+		Status:     func(c Component) *string { return &c.(*VPC).Status },
+		Diff:       (*FSMMessage).DiffVPCs,
+		Retain:     true,
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "network",
+		Collection: "networks",
+		Name:       func(c Component) string { return c.(*Network).Name },
+		Status:     func(c Component) *string { return &c.(*Network).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*Network).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*Network).HasChanged(o.(*Network)) },
+		ProviderData: func(c, o Component) {
+			c.(*Network).copyProviderData(o.(*Network))
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "instance",
+		Collection: "instances",
+		Name:       func(c Component) string { return c.(*Instance).Name },
+		Status:     func(c Component) *string { return &c.(*Instance).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*Instance).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*Instance).HasChanged(o.(*Instance)) },
+		ProviderData: func(c, o Component) {
+			c.(*Instance).copyProviderData(o.(*Instance))
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "firewall",
+		Collection: "firewalls",
+		Name:       func(c Component) string { return c.(*Firewall).Name },
+		Status:     func(c Component) *string { return &c.(*Firewall).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*Firewall).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*Firewall).HasChanged(o.(*Firewall)) },
+		ProviderData: func(c, o Component) {
+			c.(*Firewall).copyProviderData(o.(*Firewall))
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "nat",
+		Collection: "nats",
+		Name:       func(c Component) string { return c.(*Nat).Name },
+		Status:     func(c Component) *string { return &c.(*Nat).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*Nat).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*Nat).HasChanged(o.(*Nat)) },
+		ProviderData: func(c, o Component) {
+			c.(*Nat).copyProviderData(o.(*Nat))
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "elb",
+		Collection: "elbs",
+		Name:       func(c Component) string { return c.(*ELB).Name },
+		Status:     func(c Component) *string { return &c.(*ELB).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*ELB).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*ELB).HasChanged(o.(*ELB)) },
+		ProviderData: func(c, o Component) {
+			c.(*ELB).copyProviderData(o.(*ELB))
+		},
+		References: func(c Component, collection, name string) bool {
+			return collection == "instances" && c.(*ELB).hasInstance(name)
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "alb",
+		Collection: "albs",
+		Name:       func(c Component) string { return c.(*ALB).Name },
+		Status:     func(c Component) *string { return &c.(*ALB).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*ALB).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*ALB).HasChanged(o.(*ALB)) },
+		ProviderData: func(c, o Component) {
+			c.(*ALB).copyProviderData(o.(*ALB))
+		},
+		References: func(c Component, collection, name string) bool {
+			return collection == "instances" && c.(*ALB).hasInstance(name)
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "iam_role",
+		Collection: "iam_roles",
+		Name:       func(c Component) string { return c.(*IAMRole).Name },
+		Status:     func(c Component) *string { return &c.(*IAMRole).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*IAMRole).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*IAMRole).HasChanged(o.(*IAMRole)) },
+		ProviderData: func(c, o Component) {
+			c.(*IAMRole).copyProviderData(o.(*IAMRole))
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "autoscaling_group",
+		Collection: "autoscaling_groups",
+		Name:       func(c Component) string { return c.(*AutoscalingGroup).Name },
+		Status:     func(c Component) *string { return &c.(*AutoscalingGroup).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*AutoscalingGroup).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*AutoscalingGroup).HasChanged(o.(*AutoscalingGroup)) },
+		ProviderData: func(c, o Component) {
+			c.(*AutoscalingGroup).copyProviderData(o.(*AutoscalingGroup))
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "s3",
+		Collection: "s3s",
+		Name:       func(c Component) string { return c.(*S3).Name },
+		Status:     func(c Component) *string { return &c.(*S3).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*S3).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*S3).HasChanged(o.(*S3)) },
+		ProviderData: func(c, o Component) {
+			c.(*S3).copyProviderData(o.(*S3))
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "route53",
+		Collection: "route53s",
+		Name:       func(c Component) string { return c.(*Route53Zone).Name },
+		Status:     func(c Component) *string { return &c.(*Route53Zone).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*Route53Zone).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*Route53Zone).HasChanged(o.(*Route53Zone)) },
+		ProviderData: func(c, o Component) {
+			c.(*Route53Zone).copyProviderData(o.(*Route53Zone))
+		},
+		References: func(c Component, collection, name string) bool {
+			return c.(*Route53Zone).hasReference(collection, name)
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "rds_cluster",
+		Collection: "rds_clusters",
+		Name:       func(c Component) string { return c.(*RDSCluster).Name },
+		Status:     func(c Component) *string { return &c.(*RDSCluster).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*RDSCluster).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*RDSCluster).HasChanged(o.(*RDSCluster)) },
+		ProviderData: func(c, o Component) {
+			c.(*RDSCluster).copyProviderData(o.(*RDSCluster))
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "rds_instance",
+		Collection: "rds_instances",
+		Name:       func(c Component) string { return c.(*RDSInstance).Name },
+		Status:     func(c Component) *string { return &c.(*RDSInstance).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*RDSInstance).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*RDSInstance).HasChanged(o.(*RDSInstance)) },
+		ProviderData: func(c, o Component) {
+			c.(*RDSInstance).copyProviderData(o.(*RDSInstance))
+		},
+	})
+	RegisterComponentType(ComponentType{
+		Type:       "ebs_volume",
+		Collection: "ebs_volumes",
+		Name:       func(c Component) string { return c.(*EBSVolume).Name },
+		Status:     func(c Component) *string { return &c.(*EBSVolume).Status },
+		Changes:    func(c Component) *[]Change { return &c.(*EBSVolume).Changes },
+		HasChanged: func(c, o Component) []Change { return c.(*EBSVolume).HasChanged(o.(*EBSVolume)) },
+		ProviderData: func(c, o Component) {
+			c.(*EBSVolume).copyProviderData(o.(*EBSVolume))
+		},
+	})
+}
+
+// RegisterComponentType adds a component type to the registry. The
+// collection and its action lists must be declared on the FSMMessage, and
+// the type must provide accessors for the name and status of its components
+func RegisterComponentType(t ComponentType) {
+	if _, ok := collectionFields[t.Collection]; !ok {
+		panic("component collection " + t.Collection + " is not declared on the fsm message")
+	}
+	if t.Name == nil || t.Status == nil {
+		panic("component type " + t.Type + " must register name and status accessors")
+	}
+
+	ComponentTypes = append(ComponentTypes, t)
+}
+
+// FindComponentType returns the registered component type of a collection
+func FindComponentType(collection string) *ComponentType {
+	for i, t := range ComponentTypes {
+		if t.Collection == collection {
+			return &ComponentTypes[i]
+		}
+	}
+	return nil
+}
+
+// List returns the json name of the list holding the components an action
+// will be applied to
+func (t ComponentType) List(action string) string {
+	return t.Collection + "_to_" + action
+}
+
+// Actions returns all actions that can be applied to the component type
+func (t ComponentType) Actions() []string {
+	var actions []string
+
+	for _, action := range []string{ACTIONCREATE, ACTIONUPDATE, ACTIONDELETE} {
+		if _, ok := collectionFields[t.List(action)]; ok {
+			actions = append(actions, action)
+		}
+	}
+
+	return actions
+}
+
+// Components returns the components of a collection or action list by its
+// json name. Components are returned as pointers to the stored items
+func (m *FSMMessage) Components(list string) []Component {
+	var components []Component
+
+	items := m.items(list)
+	if !items.IsValid() {
+		return components
+	}
+
+	for i := 0; i < items.Len(); i++ {
+		if c, ok := items.Index(i).Addr().Interface().(Component); ok {
+			components = append(components, c)
+		}
+	}
+
+	return components
+}
+
+// Find returns the component of a collection or action list with a given name
+func (m *FSMMessage) Find(list, name string) Component {
+	t := FindComponentType(strings.Split(list, "_to_")[0])
+	if t == nil {
+		return nil
+	}
+
+	for _, c := range m.Components(list) {
+		if t.Name(c) == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// diffComponents calculates the diff on a component type's collection
+func (m *FSMMessage) diffComponents(t ComponentType, om FSMMessage) {
+	if t.Diff != nil {
+		t.Diff(m, om)
+		return
+	}
+
+	items := m.items(t.Collection)
+	create := m.items(t.List(ACTIONCREATE))
+	update := m.items(t.List(ACTIONUPDATE))
+	remove := m.items(t.List(ACTIONDELETE))
+
+	for _, c := range m.Components(t.Collection) {
+		o := om.Find(t.Collection, t.Name(c))
+		if o == nil {
+			appendComponent(create, c)
+			continue
+		}
+
+		if t.HasChanged == nil {
+			continue
+		}
+
+		changes := t.HasChanged(c, o)
+
+		// components that can't be changed in place are deleted and
+		// created again
+		if RequiresReplacement(changes) {
+			m.addReplacement(t.Collection, t.Name(c))
+
+			d := copyComponent(o)
+			t.setStatus(d, "")
+			appendComponent(remove, d)
+
+			r := copyComponent(c)
+			t.setChanges(r, changes)
+			appendComponent(create, r)
+			continue
+		}
+
+		if update.IsValid() && len(changes) > 0 {
+			u := copyComponent(c)
+			t.setChanges(u, changes)
+			appendComponent(update, u)
+		}
+	}
+
+	for _, o := range om.Components(t.Collection) {
+		if m.Find(t.Collection, t.Name(o)) == nil {
+			d := copyComponent(o)
+			t.setStatus(d, "")
+			appendComponent(remove, d)
+		}
+	}
+
+	// retry any updates that did not complete on the previous build
+	if update.IsValid() {
+		for _, o := range om.Components(t.List(ACTIONUPDATE)) {
+			if t.status(o) == "completed" {
+				continue
+			}
+			if m.Find(t.Collection, t.Name(o)) != nil && m.Find(t.List(ACTIONUPDATE), t.Name(o)) == nil {
+				appendComponent(update, o)
+			}
+		}
+	}
+
+	existing := reflect.Zero(items.Type())
+	for _, c := range m.Components(t.Collection) {
+		if m.Find(t.List(ACTIONCREATE), t.Name(c)) == nil {
+			existing = reflect.Append(existing, reflect.ValueOf(c).Elem())
+		}
+	}
+	items.Set(existing)
+}
+
+// updateReferences updates every component that references a replaced
+// component, so it picks up the values generated for the new component
+func (m *FSMMessage) updateReferences() {
+	for _, t := range ComponentTypes {
+		update := m.items(t.List(ACTIONUPDATE))
+		if t.References == nil || !update.IsValid() {
+			continue
+		}
+
+		for _, c := range m.Components(t.Collection) {
+			var changes []Change
+
+			for _, rt := range ComponentTypes {
+				for _, name := range m.Replacements[rt.Collection] {
+					if t.References(c, rt.Collection, name) {
+						changes = append(changes, Change{Field: rt.Collection, Old: name, New: name})
+					}
+				}
+			}
+
+			if len(changes) < 1 {
+				continue
+			}
+
+			if u := m.Find(t.List(ACTIONUPDATE), t.Name(c)); u != nil {
+				t.setChanges(u, append(t.changes(u), changes...))
+				continue
+			}
+
+			u := copyComponent(c)
+			t.setChanges(u, changes)
+			appendComponent(update, u)
+		}
+	}
+}
+
+// addReplacement records a component that will be deleted and created again
+func (m *FSMMessage) addReplacement(collection, name string) {
+	if m.Replacements == nil {
+		m.Replacements = make(map[string][]string)
+	}
+	m.Replacements[collection] = append(m.Replacements[collection], name)
+}
+
+// IsReplaced returns true if a component of a collection will be deleted
+// and created again
+func (m *FSMMessage) IsReplaced(collection, name string) bool {
+	for _, n := range m.Replacements[collection] {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// collection returns a collection or action list of the message by its json name
+func (m *FSMMessage) collection(list string) reflect.Value {
+	i, ok := collectionFields[list]
+	if !ok {
+		return reflect.Value{}
+	}
+	return reflect.ValueOf(m).Elem().Field(i)
+}
+
+// items returns the items of a collection or action list by its json name
+func (m *FSMMessage) items(list string) reflect.Value {
+	c := m.collection(list)
+	if !c.IsValid() {
+		return c
+	}
+	return c.FieldByName("Items")
+}
+
+// collectionIndex maps the json name of every collection declared on a
+// FSMMessage to its field index
+func collectionIndex() map[string]int {
+	fields := make(map[string]int)
+
+	t := reflect.TypeOf(FSMMessage{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type.Kind() != reflect.Struct {
+			continue
+		}
+		if items, ok := f.Type.FieldByName("Items"); !ok || items.Type.Kind() != reflect.Slice {
+			continue
+		}
+		fields[strings.Split(f.Tag.Get("json"), ",")[0]] = i
+	}
+
+	return fields
+}
+
+// copyComponent returns a pointer to a copy of a component's value
+func copyComponent(c Component) Component {
+	v := reflect.New(reflect.TypeOf(c).Elem())
+	v.Elem().Set(reflect.ValueOf(c).Elem())
+	return v.Interface().(Component)
+}
+
+// appendComponent appends the value of a component to a list's items
+func appendComponent(items reflect.Value, c Component) {
+	items.Set(reflect.Append(items, reflect.ValueOf(c).Elem()))
+}
+
+// status returns the status of a component
+func (t ComponentType) status(c Component) string {
+	return *t.Status(c)
+}
+
+// setStatus sets the status of a component
+func (t ComponentType) setStatus(c Component, status string) {
+	*t.Status(c) = status
+}
+
+// changes returns the changes recorded on a component
+func (t ComponentType) changes(c Component) []Change {
+	if t.Changes == nil {
+		return nil
+	}
+	return *t.Changes(c)
+}
+
+// setChanges records the changes found on a component
+func (t ComponentType) setChanges(c Component, changes []Change) {
+	if t.Changes != nil {
+		*t.Changes(c) = changes
+	}
+}
@@ -0,0 +1,115 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestComponentRegistry(t *testing.T) {
+	Convey("Given the registered component types", t, func() {
+		Convey("Then every collection of the fsm message should be registered", func() {
+			for list := range collectionFields {
+				if list == "datacenters" {
+					continue
+				}
+				collection := strings.Split(list, "_to_")[0]
+				So(FindComponentType(collection), ShouldNotBeNil)
+			}
+		})
+
+		Convey("Then action lists should be derived from the fsm message", func() {
+			So(FindComponentType("instances").Actions(), ShouldResemble, []string{ACTIONCREATE, ACTIONUPDATE, ACTIONDELETE})
+			So(FindComponentType("vpcs").Actions(), ShouldResemble, []string{ACTIONCREATE, ACTIONDELETE})
+		})
+
+		Convey("Then every component type should register name and status accessors", func() {
+			for _, t := range ComponentTypes {
+				So(t.Name, ShouldNotBeNil)
+				So(t.Status, ShouldNotBeNil)
+				if t.HasChanged != nil {
+					So(t.Changes, ShouldNotBeNil)
+				}
+			}
+		})
+
+		Convey("When registering a component type without a status accessor", func() {
+			register := func() {
+				RegisterComponentType(ComponentType{
+					Type:       "network",
+					Collection: "networks",
+					Name:       func(c Component) string { return c.(*Network).Name },
+				})
+			}
+
+			Convey("Then it should not be registered", func() {
+				count := len(ComponentTypes)
+				So(register, ShouldPanic)
+				So(len(ComponentTypes), ShouldEqual, count)
+			})
+		})
+	})
+
+	Convey("Given a fsm message with rds instances", t, func() {
+		var m FSMMessage
+		m.RDSInstancesToCreate.Items = []RDSInstance{{Name: "db-1", Status: "errored"}}
+		m.RDSClustersToUpdate.Items = []RDSCluster{{Name: "db", Status: "completed"}}
+
+		Convey("When generating a workflow", func() {
+			err := m.GenerateWorkflow("create")
+
+			Convey("Then it should reset the status of every component", func() {
+				So(err, ShouldBeNil)
+				So(m.RDSInstancesToCreate.Items[0].Status, ShouldEqual, "")
+				So(m.RDSClustersToUpdate.Items[0].Status, ShouldEqual, "")
+			})
+		})
+	})
+
+	Convey("Given a fsm message with existing components", t, func() {
+		var m FSMMessage
+		m.VPCs.Items = []VPC{{VpcID: "vpc-1", Status: "completed"}}
+		m.Networks.Items = []Network{{Name: "web", Status: "completed"}}
+		m.RDSInstances.Items = []RDSInstance{{Name: "db-1", Status: "completed"}}
+
+		Convey("When deleting all components", func() {
+			m.DeleteAll()
+
+			Convey("Then every component but the vpc should be deleted", func() {
+				So(len(m.VPCsToDelete.Items), ShouldEqual, 0)
+				So(len(m.NetworksToDelete.Items), ShouldEqual, 1)
+				So(m.NetworksToDelete.Items[0].Status, ShouldEqual, "")
+				So(len(m.RDSInstancesToDelete.Items), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When finding a component", func() {
+			c := m.Find("networks", "web")
+
+			Convey("Then it should return the stored component", func() {
+				So(c, ShouldNotBeNil)
+				c.(*Network).Subnet = "10.0.0.0/24"
+				So(m.Networks.Items[0].Subnet, ShouldEqual, "10.0.0.0/24")
+				So(m.Find("networks", "db"), ShouldBeNil)
+			})
+		})
+
+		Convey("When marshalling the message", func() {
+			data, err := json.Marshal(m)
+			var keys map[string]json.RawMessage
+			_ = json.Unmarshal(data, &keys)
+
+			Convey("Then collections should keep their json layout", func() {
+				So(err, ShouldBeNil)
+				So(string(keys["networks_to_create"]), ShouldEqual, `{"started":"","finished":"","status":"","items":null}`)
+				So(string(keys["ebs_volumes"]), ShouldEqual, `{"started":"","finished":"","status":"","items":null}`)
+			})
+		})
+	})
+}
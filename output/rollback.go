@@ -0,0 +1,84 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"reflect"
+	"strings"
+)
+
+// State returns a copy of the message holding its components, but none of
+// the components it would create, update or delete
+func (m *FSMMessage) State() *FSMMessage {
+	s := *m
+	s.Workflow.Arcs = nil
+	s.Replacements = nil
+
+	if m.SecretRefs != nil {
+		s.SecretRefs = make(map[string]string)
+		for k, v := range m.SecretRefs {
+			s.SecretRefs[k] = v
+		}
+	}
+
+	for list := range collectionFields {
+		items := s.items(list)
+		if strings.Contains(list, "_to_") {
+			items.Set(reflect.Zero(items.Type()))
+			continue
+		}
+		items.Set(reflect.AppendSlice(reflect.Zero(items.Type()), items))
+	}
+
+	return &s
+}
+
+// Applied returns the state a partially applied build has left a service
+// in. Every create, update and delete of the build that has completed is
+// applied to the state of the previous mapping
+func (m *FSMMessage) Applied(om FSMMessage) *FSMMessage {
+	a := om.State()
+
+	for _, t := range ComponentTypes {
+		items := a.items(t.Collection)
+
+		for _, c := range m.Components(t.List(ACTIONDELETE)) {
+			if t.status(c) == "completed" {
+				removeComponent(t, items, t.Name(c))
+			}
+		}
+
+		for _, action := range []string{ACTIONUPDATE, ACTIONCREATE} {
+			for _, c := range m.Components(t.List(action)) {
+				if t.status(c) != "completed" {
+					continue
+				}
+
+				removeComponent(t, items, t.Name(c))
+
+				v := copyComponent(c)
+				t.setChanges(v, nil)
+				appendComponent(items, v)
+			}
+		}
+	}
+
+	return a
+}
+
+// removeComponent removes a named component from a collection's items
+func removeComponent(t ComponentType, items reflect.Value, name string) {
+	kept := reflect.Zero(items.Type())
+
+	for i := 0; i < items.Len(); i++ {
+		c, ok := items.Index(i).Addr().Interface().(Component)
+		if ok && t.Name(c) == name {
+			continue
+		}
+		kept = reflect.Append(kept, items.Index(i))
+	}
+
+	items.Set(kept)
+}
@@ -0,0 +1,56 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestApplied(t *testing.T) {
+	Convey("Given a previous mapping", t, func() {
+		var om FSMMessage
+		om.Instances.Items = []Instance{
+			{Name: "web-1", Type: "t2.micro"},
+			{Name: "web-2", Type: "t2.micro"},
+		}
+		om.InstancesToCreate.Items = []Instance{{Name: "web-2", Status: "completed"}}
+
+		Convey("When I get its state", func() {
+			s := om.State()
+
+			Convey("Then it should hold a copy of its components only", func() {
+				So(len(s.Instances.Items), ShouldEqual, 2)
+				So(len(s.InstancesToCreate.Items), ShouldEqual, 0)
+				s.Instances.Items[0].Type = "t2.large"
+				So(om.Instances.Items[0].Type, ShouldEqual, "t2.micro")
+			})
+		})
+
+		Convey("When a build has partially been applied to it", func() {
+			var m FSMMessage
+			m.InstancesToCreate.Items = []Instance{
+				{Name: "web-3", Status: "completed"},
+				{Name: "web-4", Status: "errored"},
+			}
+			m.InstancesToUpdate.Items = []Instance{
+				{Name: "web-1", Type: "t2.large", Status: "completed", Changes: []Change{{Field: "instance_type"}}},
+			}
+			m.InstancesToDelete.Items = []Instance{{Name: "web-2", Status: "completed"}}
+
+			a := m.Applied(om)
+
+			Convey("Then only completed changes should be applied", func() {
+				So(len(a.Instances.Items), ShouldEqual, 2)
+				So(a.Instances.Items[0].Name, ShouldEqual, "web-1")
+				So(a.Instances.Items[0].Type, ShouldEqual, "t2.large")
+				So(a.Instances.Items[0].Changes, ShouldBeNil)
+				So(a.Instances.Items[1].Name, ShouldEqual, "web-3")
+				So(om.Instances.Items[0].Type, ShouldEqual, "t2.micro")
+			})
+		})
+	})
+}
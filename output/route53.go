@@ -4,7 +4,11 @@
 
 package output
 
-import "reflect"
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
 
 // Record stores the entries for a zone
 type Record struct {
@@ -28,17 +32,45 @@ type Route53Zone struct {
 	AccessKeyID      string            `json:"aws_access_key_id"`
 	SecretAccessKey  string            `json:"aws_secret_access_key"`
 	Service          string            `json:"service"`
+	Changes          []Change          `json:"changes,omitempty"`
 	Status           string            `json:"status"`
 	Exists           bool
 }
 
-// HasChanged diff's the two items and returns true if there have been any changes
-func (z *Route53Zone) HasChanged(oz *Route53Zone) bool {
-	if len(z.Records) != len(oz.Records) {
-		return true
+// HasChanged diff's the two items and returns any changes between them
+func (z *Route53Zone) HasChanged(oz *Route53Zone) []Change {
+	var c []Change
+
+	for _, r := range oz.Records {
+		if z.findRecord(r.Entry, r.Type) == nil {
+			c = append(c, Change{Field: "records", Old: r.String()})
+		}
+	}
+
+	for _, r := range z.Records {
+		or := oz.findRecord(r.Entry, r.Type)
+		if or == nil {
+			c = append(c, Change{Field: "records", New: r.String()})
+		} else if !reflect.DeepEqual(r, *or) {
+			c = append(c, Change{Field: "records", Old: or.String(), New: r.String()})
+		}
+	}
+
+	return c
+}
+
+func (z *Route53Zone) findRecord(entry, rtype string) *Record {
+	for i, r := range z.Records {
+		if r.Entry == entry && r.Type == rtype {
+			return &z.Records[i]
+		}
 	}
+	return nil
+}
 
-	return !reflect.DeepEqual(z.Records, oz.Records)
+// String returns a human readable representation of the record
+func (r Record) String() string {
+	return r.Entry + " " + r.Type + " " + strconv.FormatInt(r.TTL, 10) + " [" + strings.Join(r.Values, ", ") + "]"
 }
 
 // GetTags returns a components tags
@@ -55,3 +87,28 @@ func (z Route53Zone) ProviderID() string {
 func (z Route53Zone) ComponentName() string {
 	return z.Name
 }
+
+// copyProviderData copies any values generated by aws from a previous route53 zone
+func (z *Route53Zone) copyProviderData(oz *Route53Zone) {
+	z.HostedZoneID = oz.HostedZoneID
+	z.DatacenterName = "$(datacenters.items.0.name)"
+	z.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	z.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	z.DatacenterRegion = "$(datacenters.items.0.region)"
+	z.VPCID = "$(vpcs.items.0.vpc_id)"
+}
+
+// hasReference returns true if any record value references a named
+// component of a collection
+func (z *Route53Zone) hasReference(collection, name string) bool {
+	ref := `$(` + collection + `.items.#[name="` + name + `"].`
+
+	for _, r := range z.Records {
+		for _, v := range r.Values {
+			if strings.HasPrefix(v, ref) {
+				return true
+			}
+		}
+	}
+	return false
+}
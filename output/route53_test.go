@@ -40,8 +40,8 @@ func TestRoute53HasChanged(t *testing.T) {
 				},
 			}
 			change := z.HasChanged(&oz)
-			Convey("Then it should return true", func() {
-				So(change, ShouldBeTrue)
+			Convey("Then it should return the changes", func() {
+				So(change, ShouldNotBeEmpty)
 			})
 		})
 
@@ -59,8 +59,8 @@ func TestRoute53HasChanged(t *testing.T) {
 				},
 			}
 			change := z.HasChanged(&oz)
-			Convey("Then it should return false", func() {
-				So(change, ShouldBeFalse)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
 			})
 		})
 	})
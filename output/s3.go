@@ -4,7 +4,7 @@
 
 package output
 
-import "reflect"
+import "strings"
 
 // S3Grantee ...
 type S3Grantee struct {
@@ -27,21 +27,47 @@ type S3 struct {
 	Grantees         []S3Grantee       `json:"grantees,omitempty"`
 	Tags             map[string]string `json:"tags"`
 	Service          string            `json:"service"`
+	Changes          []Change          `json:"changes,omitempty"`
 	Status           string            `json:"status"`
 	Exists           bool
 }
 
-// HasChanged diff's the two items and returns true if there have been any changes
-func (s *S3) HasChanged(os *S3) bool {
-	if s.ACL != os.ACL {
-		return true
+// HasChanged diff's the two items and returns any changes between them.
+// A bucket can't be moved to another region, so changing its location
+// requires the bucket to be replaced
+func (s *S3) HasChanged(os *S3) []Change {
+	var c []Change
+
+	c = diffReplace(c, "bucket_location", os.BucketLocation, s.BucketLocation)
+	c = diffString(c, "acl", os.ACL, s.ACL)
+
+	for _, g := range os.Grantees {
+		if hasGrantee(s.Grantees, g) != true {
+			c = append(c, Change{Field: "grantees", Old: g.String()})
+		}
 	}
 
-	if len(s.Grantees) < 1 && len(os.Grantees) < 1 {
-		return false
+	for _, g := range s.Grantees {
+		if hasGrantee(os.Grantees, g) != true {
+			c = append(c, Change{Field: "grantees", New: g.String()})
+		}
 	}
 
-	return !reflect.DeepEqual(s.Grantees, os.Grantees)
+	return c
+}
+
+// String returns a human readable representation of the grantee
+func (g S3Grantee) String() string {
+	return strings.Join([]string{g.Type, g.ID, g.Permissions}, ":")
+}
+
+func hasGrantee(grantees []S3Grantee, grantee S3Grantee) bool {
+	for _, g := range grantees {
+		if g == grantee {
+			return true
+		}
+	}
+	return false
 }
 
 // GetTags returns a components tags
@@ -58,3 +84,11 @@ func (s S3) ProviderID() string {
 func (s S3) ComponentName() string {
 	return s.Name
 }
+
+// copyProviderData copies any values generated by aws from a previous s3 bucket
+func (s *S3) copyProviderData(os *S3) {
+	s.DatacenterName = "$(datacenters.items.0.name)"
+	s.SecretAccessKey = "$(datacenters.items.0.aws_secret_access_key)"
+	s.AccessKeyID = "$(datacenters.items.0.aws_access_key_id)"
+	s.DatacenterRegion = "$(datacenters.items.0.region)"
+}
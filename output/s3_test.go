@@ -40,8 +40,8 @@ func TestS3HasChanged(t *testing.T) {
 				},
 			}
 			change := s.HasChanged(&os)
-			Convey("Then it should return true", func() {
-				So(change, ShouldBeTrue)
+			Convey("Then it should return the changes", func() {
+				So(change, ShouldNotBeEmpty)
 			})
 		})
 
@@ -57,8 +57,8 @@ func TestS3HasChanged(t *testing.T) {
 				},
 			}
 			change := s.HasChanged(&os)
-			Convey("Then it should return false", func() {
-				So(change, ShouldBeFalse)
+			Convey("Then it should return no changes", func() {
+				So(change, ShouldBeEmpty)
 			})
 		})
 	})
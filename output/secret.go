@@ -0,0 +1,77 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"fmt"
+
+	"github.com/ernestio/aws-definition-mapper/secret"
+)
+
+// secretField : a field of a component that can hold a secret reference
+type secretField struct {
+	key   string
+	value *string
+}
+
+// CheckSecrets returns an error if a secret reference on the message can't
+// be resolved. Resolved values are discarded, so the message only ever holds
+// the references and can be stored as it is. Secrets are resolved with
+// secret.Resolve by whoever needs their value
+func (m *FSMMessage) CheckSecrets() error {
+	for _, f := range m.secretFields() {
+		if !secret.IsReference(*f.value) {
+			continue
+		}
+
+		if _, err := secret.Resolve(*f.value); err != nil {
+			return fmt.Errorf("%s: %s", f.key, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// RedactSecrets replaces every resolved secret on a mapping stored by a
+// previous version, which sent resolved secrets to the fsm, with the
+// reference it was resolved from
+func (m *FSMMessage) RedactSecrets() {
+	for _, f := range m.secretFields() {
+		if ref, ok := m.SecretRefs[f.key]; ok {
+			*f.value = ref
+		}
+	}
+}
+
+// secretFields lists every field on the message that can hold a secret
+func (m *FSMMessage) secretFields() []secretField {
+	var fields []secretField
+
+	for _, items := range [][]Instance{m.Instances.Items, m.InstancesToCreate.Items, m.InstancesToUpdate.Items, m.InstancesToDelete.Items} {
+		for i := range items {
+			fields = append(fields, secretField{"instances." + items[i].Name + ".user_data", &items[i].UserData})
+		}
+	}
+
+	for _, items := range [][]AutoscalingGroup{m.AutoscalingGroups.Items, m.AutoscalingGroupsToCreate.Items, m.AutoscalingGroupsToUpdate.Items, m.AutoscalingGroupsToDelete.Items} {
+		for i := range items {
+			fields = append(fields, secretField{"autoscaling_groups." + items[i].Name + ".launch_configuration.user_data", &items[i].LaunchConfiguration.UserData})
+		}
+	}
+
+	for _, items := range [][]RDSCluster{m.RDSClusters.Items, m.RDSClustersToCreate.Items, m.RDSClustersToUpdate.Items, m.RDSClustersToDelete.Items} {
+		for i := range items {
+			fields = append(fields, secretField{"rds_clusters." + items[i].Name + ".database_password", &items[i].DatabasePassword})
+		}
+	}
+
+	for _, items := range [][]RDSInstance{m.RDSInstances.Items, m.RDSInstancesToCreate.Items, m.RDSInstancesToUpdate.Items, m.RDSInstancesToDelete.Items} {
+		for i := range items {
+			fields = append(fields, secretField{"rds_instances." + items[i].Name + ".database_password", &items[i].DatabasePassword})
+		}
+	}
+
+	return fields
+}
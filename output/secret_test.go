@@ -0,0 +1,60 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSecrets(t *testing.T) {
+	Convey("Given a message with secret references", t, func() {
+		os.Setenv("OUTPUT_TEST_PASSWORD", "s3cr3tpassword")
+		defer os.Unsetenv("OUTPUT_TEST_PASSWORD")
+
+		var m FSMMessage
+		m.RDSInstances.Items = append(m.RDSInstances.Items, RDSInstance{Name: "db", DatabasePassword: "secret://env/OUTPUT_TEST_PASSWORD"})
+		m.Instances.Items = append(m.Instances.Items, Instance{Name: "web-1", UserData: "#!/bin/sh"})
+		m.Diff(FSMMessage{})
+
+		Convey("When checking the secrets", func() {
+			err := m.CheckSecrets()
+			data, _ := json.Marshal(m)
+			Convey("Then the message should only hold the references", func() {
+				So(err, ShouldBeNil)
+				So(m.RDSInstancesToCreate.Items[0].DatabasePassword, ShouldEqual, "secret://env/OUTPUT_TEST_PASSWORD")
+				So(m.InstancesToCreate.Items[0].UserData, ShouldEqual, "#!/bin/sh")
+				So(string(data), ShouldNotContainSubstring, "s3cr3tpassword")
+			})
+		})
+
+		Convey("When a secret can't be resolved", func() {
+			m.RDSInstancesToCreate.Items[0].DatabasePassword = "secret://env/OUTPUT_TEST_MISSING"
+			err := m.CheckSecrets()
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "rds_instances.db.database_password: environment variable 'OUTPUT_TEST_MISSING' is not set")
+			})
+		})
+	})
+
+	Convey("Given a stored mapping holding resolved secrets", t, func() {
+		var m FSMMessage
+		m.RDSInstances.Items = append(m.RDSInstances.Items, RDSInstance{Name: "db", DatabasePassword: "s3cr3tpassword"})
+		m.SecretRefs = map[string]string{"rds_instances.db.database_password": "secret://env/OUTPUT_TEST_PASSWORD"}
+
+		Convey("When redacting the secrets", func() {
+			m.RedactSecrets()
+			data, _ := json.Marshal(m)
+			Convey("Then every secret should be replaced with its reference", func() {
+				So(m.RDSInstances.Items[0].DatabasePassword, ShouldEqual, "secret://env/OUTPUT_TEST_PASSWORD")
+				So(string(data), ShouldNotContainSubstring, "s3cr3tpassword")
+			})
+		})
+	})
+}
@@ -26,3 +26,22 @@ func (v *VPC) HasChanged(ov *VPC) bool {
 
 	return false
 }
+
+// GetTags returns a components tags
+func (v VPC) GetTags() map[string]string {
+	return v.Tags
+}
+
+// ProviderID returns a components provider id
+func (v VPC) ProviderID() string {
+	return v.VpcID
+}
+
+// ComponentName returns a components name. VPCs are not named, so they are
+// identified by their subnet until they have been created
+func (v VPC) ComponentName() string {
+	if v.VpcID != "" {
+		return v.VpcID
+	}
+	return v.VpcSubnet
+}
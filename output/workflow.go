@@ -0,0 +1,313 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/r3labs/graph"
+)
+
+// workflowSteps lists every step a workflow can contain. Arcs are always
+// generated in this order so the resulting workflow is deterministic
+var workflowSteps = []string{
+	"rds_instances.delete",
+	"rds_clusters.delete",
+	"autoscaling_groups.delete",
+	"elbs.delete",
+	"albs.delete",
+	"instances.delete",
+	"ebs_volumes.create",
+	"ebs_volumes.update",
+	"nats.delete",
+	"vpcs.create",
+	"networks.create",
+	"networks.update",
+	"firewalls.create",
+	"firewalls.update",
+	"iam_roles.create",
+	"iam_roles.update",
+	"rds_clusters.create",
+	"rds_clusters.update",
+	"rds_instances.create",
+	"rds_instances.update",
+	"instances.create",
+	"instances.update",
+	"elbs.create",
+	"elbs.update",
+	"albs.create",
+	"albs.update",
+	"autoscaling_groups.create",
+	"autoscaling_groups.update",
+	"nats.create",
+	"nats.update",
+	"s3s.delete",
+	"s3s.create",
+	"s3s.update",
+	"ebs_volumes.delete",
+	"firewalls.delete",
+	"iam_roles.delete",
+	"networks.delete",
+	"vpcs.delete",
+	"route53s.create",
+	"route53s.update",
+	"route53s.delete",
+}
+
+// workflowDependencies lists all steps that need to have completed before
+// a step can be started. Volumes are the only replaceable component created
+// before its deletes, as instance updates both attach new volumes and detach
+// deleted ones. Volumes have no unique name, and only volumes that are not
+// attached to an instance can be replaced, so they don't need to be deleted
+// before they are created again
+var workflowDependencies = map[string][]string{
+	"rds_clusters.delete":       {"rds_instances.delete"},
+	"elbs.delete":               {"autoscaling_groups.delete"},
+	"instances.delete":          {"elbs.delete", "albs.delete"},
+	"networks.create":           {"vpcs.create", "networks.delete"},
+	"networks.update":           {"networks.create"},
+	"firewalls.create":          {"vpcs.create"},
+	"firewalls.update":          {"firewalls.create"},
+	"rds_clusters.create":       {"networks.create", "firewalls.create", "firewalls.update", "rds_clusters.delete"},
+	"rds_clusters.update":       {"networks.create", "firewalls.create", "firewalls.update"},
+	"rds_instances.create":      {"rds_clusters.create", "rds_clusters.update", "networks.create", "firewalls.create", "firewalls.update", "rds_instances.delete"},
+	"rds_instances.update":      {"rds_clusters.create", "rds_clusters.update", "networks.create", "firewalls.create", "firewalls.update"},
+	"instances.create":          {"networks.create", "firewalls.create", "firewalls.update", "iam_roles.create", "iam_roles.update", "ebs_volumes.create", "instances.delete"},
+	"instances.update":          {"networks.create", "firewalls.create", "firewalls.update", "iam_roles.create", "iam_roles.update", "ebs_volumes.create"},
+	"elbs.create":               {"networks.create", "firewalls.create", "firewalls.update", "instances.create", "instances.update", "elbs.delete"},
+	"elbs.update":               {"networks.create", "firewalls.create", "firewalls.update", "instances.create", "instances.update"},
+	"albs.create":               {"networks.create", "firewalls.create", "firewalls.update", "instances.create", "instances.update", "albs.delete"},
+	"albs.update":               {"networks.create", "firewalls.create", "firewalls.update", "instances.create", "instances.update"},
+	"autoscaling_groups.create": {"networks.create", "firewalls.create", "firewalls.update", "elbs.create", "elbs.update", "autoscaling_groups.delete"},
+	"autoscaling_groups.update": {"networks.create", "firewalls.create", "firewalls.update", "elbs.create", "elbs.update"},
+	"nats.create":               {"networks.create", "nats.delete"},
+	"s3s.create":                {"s3s.delete"},
+	"nats.update":               {"networks.create", "networks.update", "nats.create"},
+	"ebs_volumes.update":        {"ebs_volumes.create"},
+	"ebs_volumes.delete":        {"instances.delete", "instances.update"},
+	"firewalls.delete":          {"instances.delete", "instances.update", "elbs.delete", "elbs.update", "albs.delete", "albs.update", "autoscaling_groups.delete", "autoscaling_groups.update", "rds_instances.delete", "rds_instances.update", "rds_clusters.delete", "rds_clusters.update", "firewalls.update"},
+	"networks.delete":           {"instances.delete", "elbs.delete", "albs.delete", "autoscaling_groups.delete", "nats.delete", "rds_instances.delete", "rds_clusters.delete"},
+	"iam_roles.delete":          {"instances.delete", "instances.update"},
+	"vpcs.delete":               {"networks.delete", "firewalls.delete"},
+	"route53s.create":           {"instances.create", "instances.update", "elbs.create", "elbs.update", "rds_instances.create", "rds_instances.update", "rds_clusters.create", "rds_clusters.update"},
+	"route53s.update":           {"instances.create", "instances.update", "elbs.create", "elbs.update", "rds_instances.create", "rds_instances.update", "rds_clusters.create", "rds_clusters.update"},
+}
+
+// workflowActions lists the actions that can be part of a workflow type
+var workflowActions = map[string][]string{
+	"create": {"create", "update", "delete"},
+	"delete": {"delete"},
+}
+
+// ErrUnsupportedWorkflow : returned when a workflow can't be built from the dependency graph
+var ErrUnsupportedWorkflow = errors.New("workflow can not be generated from component dependencies")
+
+// stepCounts returns the number of components each workflow step will process
+func (m *FSMMessage) stepCounts() map[string]int {
+	counts := make(map[string]int)
+
+	for _, t := range ComponentTypes {
+		for _, action := range t.Actions() {
+			counts[t.Collection+"."+action] = len(m.Components(t.List(action)))
+		}
+	}
+
+	return counts
+}
+
+// BuildArcs generates the workflow arcs for a service action (create or delete)
+// from the dependencies between the components that are being changed.
+// Steps that don't depend on each other are placed on parallel branches
+func (m *FSMMessage) BuildArcs(action string) ([]graph.Edge, error) {
+	actions, ok := workflowActions[action]
+	if !ok {
+		return nil, ErrUnsupportedWorkflow
+	}
+
+	counts := m.stepCounts()
+
+	active := make(map[string]bool)
+	for _, s := range workflowSteps {
+		if counts[s] > 0 && isOneOf(actions, stepAction(s)) {
+			active[s] = true
+		}
+	}
+
+	// resolve dependencies between active steps, skipping over any steps
+	// that have nothing to do
+	deps := make(map[string][]string)
+	for s := range active {
+		deps[s] = activeDependencies(s, active, make(map[string]bool))
+	}
+
+	for s := range active {
+		if _, err := ancestors(s, deps, make(map[string]bool), make(map[string]bool)); err != nil {
+			return nil, err
+		}
+	}
+
+	deps = reduceDependencies(deps)
+
+	arcs := []graph.Edge{
+		{From: "created", To: "started", Event: "service." + action},
+	}
+
+	dependents := make(map[string]bool)
+	for _, d := range deps {
+		for _, s := range d {
+			dependents[s] = true
+		}
+	}
+
+	for _, s := range workflowSteps {
+		if !active[s] {
+			continue
+		}
+
+		if len(deps[s]) == 0 {
+			arcs = append(arcs, graph.Edge{From: "started", To: stepStarted(s), Event: s})
+		}
+
+		for _, d := range deps[s] {
+			arcs = append(arcs, graph.Edge{From: stepFinished(d), To: stepStarted(s), Event: s})
+		}
+
+		arcs = append(arcs, graph.Edge{From: stepStarted(s), To: stepFinished(s), Event: s + ".done"})
+	}
+
+	finished := false
+	for _, s := range workflowSteps {
+		if active[s] && !dependents[s] {
+			arcs = append(arcs, graph.Edge{From: stepFinished(s), To: "done", Event: "service." + action + ".done"})
+			finished = true
+		}
+	}
+
+	if !finished {
+		arcs = append(arcs, graph.Edge{From: "started", To: "done", Event: "service." + action + ".done"})
+	}
+
+	return append(arcs,
+		graph.Edge{From: "pre-failed", To: "failed", Event: "to_error"},
+		graph.Edge{From: "failed", To: "errored", Event: "service." + action + ".error"},
+	), nil
+}
+
+// activeDependencies returns all active steps a step depends on. Inactive
+// dependencies are replaced by their own dependencies so ordering is kept
+func activeDependencies(s string, active, seen map[string]bool) []string {
+	var deps []string
+
+	for _, d := range workflowDependencies[s] {
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+
+		if active[d] {
+			deps = append(deps, d)
+			continue
+		}
+
+		deps = append(deps, activeDependencies(d, active, seen)...)
+	}
+
+	return deps
+}
+
+// ancestors returns every step that must complete before a given step,
+// failing if the dependencies contain a cycle
+func ancestors(s string, deps map[string][]string, visiting, found map[string]bool) (map[string]bool, error) {
+	visiting[s] = true
+
+	for _, d := range deps[s] {
+		if visiting[d] {
+			return nil, errors.New("workflow dependency cycle found on " + d)
+		}
+		if found[d] {
+			continue
+		}
+		found[d] = true
+
+		if _, err := ancestors(d, deps, visiting, found); err != nil {
+			return nil, err
+		}
+	}
+
+	visiting[s] = false
+
+	return found, nil
+}
+
+// reduceDependencies removes any dependency that is already implied by
+// another dependency of the same step
+func reduceDependencies(deps map[string][]string) map[string][]string {
+	reduced := make(map[string][]string)
+
+	for s, ds := range deps {
+		for _, d := range ds {
+			implied := false
+			for _, o := range ds {
+				if o == d {
+					continue
+				}
+				a, _ := ancestors(o, deps, make(map[string]bool), make(map[string]bool))
+				if a[d] {
+					implied = true
+					break
+				}
+			}
+			if !implied {
+				reduced[s] = append(reduced[s], d)
+			}
+		}
+	}
+
+	return reduced
+}
+
+func stepComponent(s string) string {
+	return strings.Split(s, ".")[0]
+}
+
+func stepAction(s string) string {
+	return strings.Split(s, ".")[1]
+}
+
+// stepStarted returns the state name used while a step is in progress
+func stepStarted(s string) string {
+	switch stepAction(s) {
+	case "create":
+		return "creating_" + stepComponent(s)
+	case "update":
+		return "updating_" + stepComponent(s)
+	case "delete":
+		return "deleting_" + stepComponent(s)
+	}
+	return stepComponent(s) + "_" + stepAction(s)
+}
+
+// stepFinished returns the state name used once a step has completed
+func stepFinished(s string) string {
+	switch stepAction(s) {
+	case "create":
+		return stepComponent(s) + "_created"
+	case "update":
+		return stepComponent(s) + "_updated"
+	case "delete":
+		return stepComponent(s) + "_deleted"
+	}
+	return stepComponent(s) + "_" + stepAction(s) + "_done"
+}
+
+func isOneOf(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
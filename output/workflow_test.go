@@ -0,0 +1,130 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package output
+
+import (
+	"testing"
+
+	"github.com/r3labs/graph"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func hasArc(arcs []graph.Edge, from, to string) bool {
+	for _, a := range arcs {
+		if a.From == from && a.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBuildArcs(t *testing.T) {
+	Convey("Given a service with new networks, instances and s3 buckets", t, func() {
+		var m FSMMessage
+		m.NetworksToCreate.Items = []Network{{Name: "web"}}
+		m.InstancesToCreate.Items = []Instance{{Name: "web-1"}}
+		m.S3sToCreate.Items = []S3{{Name: "bucket"}}
+		m.Route53sToUpdate.Items = []Route53Zone{{Name: "example.com"}}
+
+		Convey("When I build a create workflow", func() {
+			arcs, err := m.BuildArcs("create")
+
+			Convey("Then it should order dependent components", func() {
+				So(err, ShouldBeNil)
+				So(hasArc(arcs, "created", "started"), ShouldBeTrue)
+				So(hasArc(arcs, "started", "creating_networks"), ShouldBeTrue)
+				So(hasArc(arcs, "networks_created", "creating_instances"), ShouldBeTrue)
+				So(hasArc(arcs, "instances_created", "updating_route53s"), ShouldBeTrue)
+				So(hasArc(arcs, "route53s_updated", "done"), ShouldBeTrue)
+			})
+
+			Convey("Then it should run independent components in parallel", func() {
+				So(hasArc(arcs, "started", "creating_s3s"), ShouldBeTrue)
+				So(hasArc(arcs, "s3s_created", "done"), ShouldBeTrue)
+				So(hasArc(arcs, "started", "creating_instances"), ShouldBeFalse)
+			})
+
+			Convey("Then it should not include unused steps", func() {
+				So(hasArc(arcs, "started", "creating_vpcs"), ShouldBeFalse)
+				So(hasArc(arcs, "started", "deleting_networks"), ShouldBeFalse)
+			})
+		})
+
+		Convey("When I build a delete workflow", func() {
+			m.NetworksToDelete.Items = []Network{{Name: "web"}}
+			m.InstancesToDelete.Items = []Instance{{Name: "web-1"}}
+			arcs, err := m.BuildArcs("delete")
+
+			Convey("Then it should only contain delete steps", func() {
+				So(err, ShouldBeNil)
+				So(hasArc(arcs, "created", "started"), ShouldBeTrue)
+				So(hasArc(arcs, "started", "deleting_instances"), ShouldBeTrue)
+				So(hasArc(arcs, "instances_deleted", "deleting_networks"), ShouldBeTrue)
+				So(hasArc(arcs, "networks_deleted", "done"), ShouldBeTrue)
+				So(hasArc(arcs, "started", "creating_networks"), ShouldBeFalse)
+				So(hasArc(arcs, "started", "creating_s3s"), ShouldBeFalse)
+			})
+		})
+
+		Convey("When I build an unsupported workflow", func() {
+			_, err := m.BuildArcs("import")
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldEqual, ErrUnsupportedWorkflow)
+			})
+		})
+	})
+
+	Convey("Given a service without any changes", t, func() {
+		var m FSMMessage
+
+		Convey("When I build a create workflow", func() {
+			arcs, err := m.BuildArcs("create")
+
+			Convey("Then it should complete straight away", func() {
+				So(err, ShouldBeNil)
+				So(hasArc(arcs, "started", "done"), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a service with a new iam role and instances using it", t, func() {
+		var m FSMMessage
+		m.IAMRolesToCreate.Items = []IAMRole{{Name: "web"}}
+		m.InstancesToCreate.Items = []Instance{{Name: "web-1", IAMProfile: "web"}}
+
+		Convey("When I build a create workflow", func() {
+			arcs, err := m.BuildArcs("create")
+
+			Convey("Then it should create the iam role before the instances", func() {
+				So(err, ShouldBeNil)
+				So(hasArc(arcs, "started", "creating_iam_roles"), ShouldBeTrue)
+				So(hasArc(arcs, "iam_roles_created", "creating_instances"), ShouldBeTrue)
+				So(hasArc(arcs, "started", "creating_instances"), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given every workflow step", t, func() {
+		Convey("When I resolve their dependencies", func() {
+			active := make(map[string]bool)
+			for _, s := range workflowSteps {
+				active[s] = true
+			}
+
+			deps := make(map[string][]string)
+			for _, s := range workflowSteps {
+				deps[s] = activeDependencies(s, active, make(map[string]bool))
+			}
+
+			Convey("Then there should be no cycles", func() {
+				for _, s := range workflowSteps {
+					_, err := ancestors(s, deps, make(map[string]bool), make(map[string]bool))
+					So(err, ShouldBeNil)
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,54 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package secret
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DEFAULTSECRETSPATH : Directory file secrets are read from if SECRETS_PATH is not set
+const DEFAULTSECRETSPATH = "/run/secrets"
+
+// EnvProvider : Resolves secrets from environment variables
+type EnvProvider struct{}
+
+// Get returns the value of an environment variable
+func (p EnvProvider) Get(key string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", errors.New("environment variable '" + key + "' is not set")
+	}
+
+	return value, nil
+}
+
+// FileProvider : Resolves secrets from files within the secrets directory
+type FileProvider struct {
+	Path string
+}
+
+// Get returns the contents of a secret file, without any trailing newline
+func (p FileProvider) Get(key string) (string, error) {
+	dir := p.Path
+	if dir == "" {
+		dir = os.Getenv("SECRETS_PATH")
+	}
+	if dir == "" {
+		dir = DEFAULTSECRETSPATH
+	}
+
+	path := filepath.Join(dir, filepath.Clean("/"+key))
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.New("secret file '" + key + "' could not be read")
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,101 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package secret
+
+import (
+	"reflect"
+	"strings"
+)
+
+// REDACTED : Value stored or logged in place of a sensitive value
+const REDACTED = "(redacted)"
+
+// SensitiveFields : json fields holding credentials or secrets that must
+// never be logged or stored in clear text
+var SensitiveFields = []string{
+	"aws_access_key_id",
+	"aws_secret_access_key",
+	"password",
+	"database_password",
+	"user_data",
+}
+
+// Redact replaces the value of every sensitive field on a struct, and on
+// any struct, slice or map it contains, with REDACTED. Values that are
+// empty, mapping templates or secret references are kept, as they don't
+// hold a secret. It must be given a pointer
+func Redact(v interface{}) {
+	redact(reflect.ValueOf(v))
+}
+
+// RedactValue returns the redacted form of a sensitive value
+func RedactValue(value string) string {
+	if value == "" || IsReference(value) || strings.HasPrefix(value, "$(") {
+		return value
+	}
+	return REDACTED
+}
+
+func redact(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			redact(v.Elem())
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			if !f.CanSet() {
+				continue
+			}
+
+			if f.Kind() == reflect.String && isSensitive(t.Field(i)) {
+				f.SetString(RedactValue(f.String()))
+				continue
+			}
+
+			redact(f)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			redact(v.Index(i))
+		}
+	case reflect.Map:
+		// map values can't be set in place, so each value is redacted on
+		// a copy and stored again. Keys are matched as field names
+		for _, k := range v.MapKeys() {
+			e := v.MapIndex(k)
+			if e.Kind() == reflect.Interface && !e.IsNil() {
+				e = e.Elem()
+			}
+
+			c := reflect.New(e.Type()).Elem()
+			c.Set(e)
+
+			if c.Kind() == reflect.String && k.Kind() == reflect.String && isSensitiveName(k.String()) {
+				c.SetString(RedactValue(c.String()))
+			} else {
+				redact(c)
+			}
+
+			v.SetMapIndex(k, c)
+		}
+	}
+}
+
+func isSensitive(f reflect.StructField) bool {
+	return isSensitiveName(strings.Split(f.Tag.Get("json"), ",")[0])
+}
+
+func isSensitiveName(name string) bool {
+	for _, s := range SensitiveFields {
+		if name == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,75 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package secret
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testComponent struct {
+	Name            string `json:"name"`
+	AccessKeyID     string `json:"aws_access_key_id"`
+	SecretAccessKey string `json:"aws_secret_access_key"`
+	Password        string `json:"database_password,omitempty"`
+	UserData        string `json:"user_data"`
+}
+
+type testMessage struct {
+	Components []testComponent
+	Component  *testComponent
+	Password   string `json:"password"`
+	Named      map[string]testComponent
+	Values     map[string]interface{}
+}
+
+func TestRedact(t *testing.T) {
+	Convey("Given a message with credentials and secrets", t, func() {
+		m := testMessage{
+			Components: []testComponent{
+				{Name: "a", AccessKeyID: "AKIAEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI", Password: "s3cr3tpassword", UserData: "#!/bin/sh"},
+				{Name: "b", AccessKeyID: "$(datacenters.items.0.aws_access_key_id)", Password: "secret://env/DB_PASSWORD"},
+			},
+			Component: &testComponent{Name: "c", SecretAccessKey: "wJalrXUtnFEMI"},
+			Password:  "hunter22",
+			Named: map[string]testComponent{
+				"d": {Name: "d", Password: "s3cr3tpassword"},
+			},
+			Values: map[string]interface{}{
+				"name":     "e",
+				"password": "hunter22",
+				"count":    1,
+			},
+		}
+
+		Convey("When redacting it", func() {
+			Redact(&m)
+			Convey("Then every sensitive value should be redacted", func() {
+				So(m.Components[0].Name, ShouldEqual, "a")
+				So(m.Components[0].AccessKeyID, ShouldEqual, REDACTED)
+				So(m.Components[0].SecretAccessKey, ShouldEqual, REDACTED)
+				So(m.Components[0].Password, ShouldEqual, REDACTED)
+				So(m.Components[0].UserData, ShouldEqual, REDACTED)
+				So(m.Component.SecretAccessKey, ShouldEqual, REDACTED)
+				So(m.Password, ShouldEqual, REDACTED)
+			})
+
+			Convey("Then sensitive values held on maps should be redacted", func() {
+				So(m.Named["d"].Name, ShouldEqual, "d")
+				So(m.Named["d"].Password, ShouldEqual, REDACTED)
+				So(m.Values["name"], ShouldEqual, "e")
+				So(m.Values["password"], ShouldEqual, REDACTED)
+				So(m.Values["count"], ShouldEqual, 1)
+			})
+
+			Convey("Then templates, secret references and empty values should be kept", func() {
+				So(m.Components[1].AccessKeyID, ShouldEqual, "$(datacenters.items.0.aws_access_key_id)")
+				So(m.Components[1].Password, ShouldEqual, "secret://env/DB_PASSWORD")
+				So(m.Components[1].UserData, ShouldEqual, "")
+			})
+		})
+	})
+}
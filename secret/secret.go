@@ -0,0 +1,77 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package secret
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// PREFIX : Prefix of a secret reference, i.e. secret://env/DB_PASSWORD
+const PREFIX = "secret://"
+
+// Provider : Resolves the value of a secret by its key
+type Provider interface {
+	Get(key string) (string, error)
+}
+
+var (
+	mu        sync.RWMutex
+	providers = map[string]Provider{
+		"env":  EnvProvider{},
+		"file": FileProvider{},
+	}
+)
+
+// Register makes a secret provider available to references using its name
+func Register(name string, p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	providers[name] = p
+}
+
+// IsReference returns true if a value is a secret reference
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, PREFIX)
+}
+
+// Parse returns the provider name and key of a secret reference
+func Parse(ref string) (string, string, error) {
+	if !IsReference(ref) {
+		return "", "", errors.New("secret reference must start with " + PREFIX)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(ref, PREFIX), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.New("secret reference must take the form of " + PREFIX + "provider/key")
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// Resolve returns the value of a secret reference. Values that are not a
+// secret reference are returned unchanged
+func Resolve(value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+
+	name, key, err := Parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	mu.RLock()
+	p, ok := providers[name]
+	mu.RUnlock()
+
+	if !ok {
+		return "", errors.New("secret provider '" + name + "' is not supported")
+	}
+
+	return p.Get(key)
+}
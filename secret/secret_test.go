@@ -0,0 +1,112 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package secret
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testProvider map[string]string
+
+func (p testProvider) Get(key string) (string, error) {
+	if v, ok := p[key]; ok {
+		return v, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestResolve(t *testing.T) {
+	Convey("Given a value that is not a secret reference", t, func() {
+		Convey("When resolving it", func() {
+			v, err := Resolve("plaintext")
+			Convey("Then it should be returned unchanged", func() {
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, "plaintext")
+			})
+		})
+	})
+
+	Convey("Given an env secret reference", t, func() {
+		os.Setenv("SECRET_TEST_PASSWORD", "s3cr3tpassword")
+		defer os.Unsetenv("SECRET_TEST_PASSWORD")
+
+		Convey("When resolving it", func() {
+			v, err := Resolve("secret://env/SECRET_TEST_PASSWORD")
+			Convey("Then it should return the environment variable", func() {
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, "s3cr3tpassword")
+			})
+		})
+
+		Convey("When the environment variable is not set", func() {
+			_, err := Resolve("secret://env/SECRET_TEST_MISSING")
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "environment variable 'SECRET_TEST_MISSING' is not set")
+			})
+		})
+	})
+
+	Convey("Given a file secret reference", t, func() {
+		dir, _ := ioutil.TempDir("", "secrets")
+		defer os.RemoveAll(dir)
+
+		_ = os.MkdirAll(filepath.Join(dir, "db"), 0700)
+		_ = ioutil.WriteFile(filepath.Join(dir, "db", "password"), []byte("s3cr3tpassword\n"), 0600)
+
+		os.Setenv("SECRETS_PATH", dir)
+		defer os.Unsetenv("SECRETS_PATH")
+
+		Convey("When resolving it", func() {
+			v, err := Resolve("secret://file/db/password")
+			Convey("Then it should return the file contents", func() {
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, "s3cr3tpassword")
+			})
+		})
+
+		Convey("When it references a file outside of the secrets path", func() {
+			_, err := Resolve("secret://file/../../etc/passwd")
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a reference to an unknown provider", t, func() {
+		Convey("When resolving it", func() {
+			_, err := Resolve("secret://vault/db")
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "secret provider 'vault' is not supported")
+			})
+		})
+
+		Convey("When the provider is registered", func() {
+			Register("vault", testProvider{"db": "fromvault"})
+			v, err := Resolve("secret://vault/db")
+			Convey("Then it should resolve the secret through the provider", func() {
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, "fromvault")
+			})
+		})
+	})
+
+	Convey("Given a malformed secret reference", t, func() {
+		Convey("When resolving it", func() {
+			_, err := Resolve("secret://env")
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "secret reference must take the form of secret://provider/key")
+			})
+		})
+	})
+}